@@ -1,8 +1,12 @@
 package scraper
 
 import (
+	"bytes"
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,71 +16,213 @@ import (
 	"github.com/vinta/pangu"
 )
 
+// defaultConcurrency is how many pages a FiveN1 request at the same time by default
+const defaultConcurrency = 5
+
+// FiveN1 scrape rent.591.com.tw, it only holds settings,
+// so one FiveN1 can be reused and called concurrently.
 type FiveN1 struct {
-	rentals  Rentals
-	queryURL string
+	delay       time.Duration
+	concurrency int
+	limiter     *rateLimiter
+	retry       RetryPolicy
+
+	fetcher      Fetcher
+	cache        Cache
+	refreshCache bool
+	client       *http.Client
+	baseURL      string
+	header       http.Header
+	cookies      []*http.Cookie
+	cookieRegion *http.Cookie // region cookie of detail pages whose region is unknown
+	logger       Logger
+	progress     ProgressReporter
+}
+
+// scrapeRun hold the state of scraping a single section of a query
+type scrapeRun struct {
+	region       int
+	section      string
+	queryURL     string
+	cookieRegion *http.Cookie
+	emit         func(rental Rental, page PageInfo)
 
 	records int
 	pages   int
-	delay   time.Duration
 
-	wg           sync.WaitGroup
-	rw           sync.RWMutex
-	client       *http.Client
-	cookieRegion *http.Cookie
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs Errors
 }
 
-func NewFiveN1() *FiveN1 {
+// NewFiveN1 create a FiveN1 with default settings, which can be changed by options
+func NewFiveN1(options ...Option) *FiveN1 {
 	// default with Taipei
-	defaultCookie := &http.Cookie{
-		Name:  "urlJumpIp",
-		Value: "1",
-	}
+	defaultCookie := regionCookie("1")
 	defaultDelay := 10 * time.Millisecond
-	return &FiveN1{
+	f := &FiveN1{
 		delay:        defaultDelay,
+		concurrency:  defaultConcurrency,
+		retry:        DefaultRetryPolicy(),
 		cookieRegion: defaultCookie,
 		client:       &http.Client{},
+		baseURL:      URL591,
+		header:       http.Header{},
+		logger:       log.New(os.Stderr, "", log.LstdFlags),
+	}
+
+	for _, option := range options {
+		option(f)
+	}
+
+	if f.fetcher == nil {
+		f.fetcher = &HTTPFetcher{Client: f.client, Header: f.header}
+	}
+	f.fetcher = &limitingFetcher{Fetcher: f.fetcher, limiter: f.limiter}
+	if f.cache != nil {
+		f.fetcher = &CachingFetcher{Fetcher: f.fetcher, Cache: f.cache, Refresh: f.refreshCache}
 	}
+
+	return f
 }
 
-func (f *FiveN1) ScrapeRentals(query *Query) (rentals Rentals) {
-	f.setRegionCookie(strconv.Itoa(query.Region))
+// ScrapeRentals scrape every section of query.
+// Failed pages are collected into Errors and returned with the rentals which did succeed.
+func (f *FiveN1) ScrapeRentals(query *Query) (Rentals, error) {
+	return f.ScrapeRentalsContext(context.Background(), query)
+}
 
-	for _, section := range SplitSection(query) {
-		subQuery := query
-		subQuery.Section = section
-		f.queryURL, _ = subQuery.URL()
+// ScrapeRentalsContext is ScrapeRentals with a context,
+// once ctx is done no more page is requested and the rentals gathered so far are returned with ctx.Err().
+func (f *FiveN1) ScrapeRentalsContext(ctx context.Context, query *Query) (Rentals, error) {
+	var rentals Rentals
+	err := f.ScrapeRentalsFunc(ctx, query, func(rental Rental, _ PageInfo) error {
+		rentals = append(rentals, rental)
+		return nil
+	})
+
+	return rentals, err
+}
+
+// ScrapeRentalsFunc scrape every section of query and call handler with each rental as soon as its page is parsed.
+// handler is never called concurrently, returning an error from it stop the scraping and the error is returned as is.
+// Otherwise failed pages are collected into Errors, and ctx.Err() is collected once ctx is done.
+func (f *FiveN1) ScrapeRentalsFunc(ctx context.Context, query *Query, handler RentalHandler) error {
+	var errs Errors
 
-		//parse
-		f.parseFirstPage()
-		f.showQueryInfo()
+	scrapeCtx, stop := context.WithCancel(ctx)
+	defer stop()
 
-		for page := 0; page < f.pages; page++ {
-			f.wg.Add(1)
-			go f.scrapeWorker(page)
+	var mu sync.Mutex
+	var handlerErr error
+	emit := func(rental Rental, page PageInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if handlerErr != nil {
+			return
+		}
+		if err := handler(rental, page); err != nil {
+			handlerErr = err
+			stop()
 		}
+	}
 
-		f.wg.Wait()
+	cookieRegion := regionCookie(strconv.Itoa(query.Region))
 
-		// set section
-		for i := range f.rentals {
-			f.rentals[i].Section = section
+	for _, section := range SplitSection(query) {
+		if scrapeCtx.Err() != nil {
+			break
 		}
 
-		rentals = append(rentals, f.rentals...)
+		subQuery := *query
+		subQuery.Section = section
+		if subQuery.RootURL == "" || subQuery.RootURL == URL591 {
+			subQuery.RootURL = f.baseURL
+		}
+		queryURL, err := subQuery.URL()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		run := &scrapeRun{
+			region:       query.Region,
+			section:      section,
+			queryURL:     queryURL,
+			cookieRegion: cookieRegion,
+			emit:         emit,
+		}
+		f.scrapeSection(scrapeCtx, run)
 
-		f.rentals = Rentals{}
+		errs = append(errs, run.errs...)
 	}
 
-	return
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+
+	return errs.errorOrNil()
 }
 
-//ScrapeRentalDetail request r.URL then update rental
+// scrapeSection scrape every page of run.queryURL with a bounded pool of workers
+func (f *FiveN1) scrapeSection(ctx context.Context, run *scrapeRun) {
+	//parse
+	if err := f.parseFirstPage(ctx, run); err != nil {
+		run.addError(err)
+		return
+	}
+	f.showQueryInfo(run)
+
+	pages := make(chan int)
+	for i := 0; i < f.workers(run.pages); i++ {
+		run.wg.Add(1)
+		go f.scrapeWorker(ctx, run, pages)
+	}
+
+feed:
+	for page := 0; page < run.pages; page++ {
+		select {
+		case pages <- page:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(pages)
+
+	run.wg.Wait()
+}
+
+//ScrapeRentalDetail request r.URL with the region cookie of r.Region then update rental
 func (f *FiveN1) ScrapeRentalDetail(r *Rental) error {
-	res := f.request(r.URL)
+	return f.ScrapeRentalDetailContext(context.Background(), r)
+}
+
+// ScrapeRentalDetailContext is ScrapeRentalDetail with a context
+func (f *FiveN1) ScrapeRentalDetailContext(ctx context.Context, r *Rental) error {
+	_, err := f.scrapeRentalDetail(ctx, r)
+	return err
+}
+
+// scrapeRentalDetail update r with its detail page, cached report whether the page was served from cache
+func (f *FiveN1) scrapeRentalDetail(ctx context.Context, r *Rental) (cached bool, err error) {
+	res, err := f.request(ctx, r.URL, f.detailCookie(r))
+	if err != nil {
+		return false, err
+	}
 
-	doc := newDocumentFromResponse(res)
+	doc, err := newDocumentFromResponse(res)
+	if err != nil {
+		return res.cached, err
+	}
+
+	if err := expectPage(res, doc, "#main"); err != nil {
+		return res.cached, err
+	}
 
 	selection := doc.Find("#main").Find(".main_house_info.clearfix").
 		Find(".detailBox.clearfix").Find(".rightBox")
@@ -93,70 +239,223 @@ func (f *FiveN1) ScrapeRentalDetail(r *Rental) error {
 		}
 	})
 
-	return nil
+	return res.cached, nil
 }
 
-func (f *FiveN1) ScrapeRentalsDetail(rentals Rentals) {
-	for i, rental := range rentals {
-		log.Println("scraping", rental.URL)
-		_ = f.ScrapeRentalDetail(&rental)
-		rentals[i] = rental
-		time.Sleep(f.delay)
-	}
+// ScrapeRentalsDetail update every rental with its detail page,
+// rentals failed to scrape are left untouched and their DetailError are collected into Errors.
+func (f *FiveN1) ScrapeRentalsDetail(rentals Rentals) error {
+	return f.ScrapeRentalsDetailContext(context.Background(), rentals)
 }
 
-func (f *FiveN1) parseFirstPage() {
-	response := f.request(f.queryURL)
+// ScrapeRentalsDetailContext is ScrapeRentalsDetail with a context,
+// once ctx is done the remaining rentals are left untouched and ctx.Err() is collected.
+// Detail pages are requested by a bounded pool of workers, progress is reported after each rental.
+func (f *FiveN1) ScrapeRentalsDetailContext(ctx context.Context, rentals Rentals) error {
+	var errs Errors
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	progress := newProgressTracker(len(rentals), f.progressReporter())
+
+	indexes := make(chan int)
+	for i := 0; i < f.workers(len(rentals)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				rental := rentals[i]
+				cached, err := f.scrapeRentalDetail(ctx, &rental)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, &DetailError{Index: i, ID: rental.ID, URL: rental.URL, Err: err})
+					mu.Unlock()
+				} else {
+					rentals[i] = rental
+				}
+				progress.done(err)
 
-	doc := newDocumentFromResponse(response)
+				// the delay spare 591, pages served from cache don't need it
+				if !cached {
+					_ = sleep(ctx, f.delay)
+				}
+			}
+		}()
+	}
 
-	f.parseRecordsNum(doc) // Record pages number at first
+feed:
+	for i := range rentals {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].(*DetailError).Index < errs[j].(*DetailError).Index
+	})
+
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+
+	return errs.errorOrNil()
 }
 
-func (f *FiveN1) request(url string) *http.Response {
-	req, err := http.NewRequest("GET", url, nil)
+func (f *FiveN1) progressReporter() ProgressReporter {
+	if f.progress != nil {
+		return f.progress
+	}
+
+	return LogProgress(f.logger, 10)
+}
+
+func (f *FiveN1) parseFirstPage(ctx context.Context, run *scrapeRun) error {
+	response, err := f.request(ctx, run.queryURL, run.cookieRegion)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	doc, err := newDocumentFromResponse(response)
+	if err != nil {
+		return err
+	}
+
+	if err := expectPage(response, doc, ".pull-left.hasData"); err != nil {
+		return err
 	}
 
-	req.AddCookie(f.cookieRegion)
+	run.records, run.pages = parseRecordsNum(doc) // Record pages number at first
+
+	return nil
+}
+
+// request GET url, failed attempts are retried according to f.retry
+func (f *FiveN1) request(ctx context.Context, url string, cookieRegion *http.Cookie) (*Response, error) {
+	attempts := 0
+	for {
+		attempts++
+		res, retryAfter, err := f.do(ctx, url, cookieRegion)
+		if err == nil {
+			return res, nil
+		}
 
-	res, err := f.client.Do(req)
+		if ctx.Err() != nil || attempts >= f.retry.MaxAttempts || !f.retry.retryable(err) {
+			if attempts > 1 {
+				return nil, &RetryError{URL: url, Attempts: attempts, Err: err}
+			}
+			return nil, err
+		}
+
+		if sleepErr := sleep(ctx, f.retry.backoff(attempts, retryAfter)); sleepErr != nil {
+			return nil, &RetryError{URL: url, Attempts: attempts, Err: err}
+		}
+	}
+}
+
+// do fetch url once, retryAfter is parsed from the response when status is not 2xx
+func (f *FiveN1) do(ctx context.Context, url string, cookieRegion *http.Cookie) (res *Response, retryAfter time.Duration, err error) {
+	cookies := append([]*http.Cookie{}, f.cookies...)
+	cookies = append(cookies, cookieRegion)
+
+	res, err = f.fetcher.Fetch(ctx, url, cookies)
 	if err != nil {
-		log.Fatal(err)
+		return nil, 0, err
+	}
+
+	if err := checkPage(res); err != nil {
+		return nil, 0, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		retryAfter = parseRetryAfter(res.Header.Get("Retry-After"))
+		return nil, retryAfter, &StatusError{URL: url, StatusCode: res.StatusCode}
 	}
 
-	return res
+	return res, 0, nil
 }
 
-func (f *FiveN1) parseRecordsNum(doc *goquery.Document) {
+// parseRecordsNum return total records and pages of a list page
+func parseRecordsNum(doc *goquery.Document) (records int, pages int) {
 	doc.Find(".pull-left.hasData > i").Each(func(_ int, selector *goquery.Selection) {
 		recordString := stringReplacer(selector.Text())
 		replaceComma := strings.Replace(recordString, ",", "", -1)
 		totalRecord, _ := strconv.Atoi(replaceComma)
-		pages := totalRecord / itemsPerPage
+		pages = totalRecord / itemsPerPage
 
 		if totalRecord%itemsPerPage > 0 {
 			pages += 1
 		}
 
-		f.records = totalRecord
-		f.pages = pages
+		records = totalRecord
 	})
+
+	return records, pages
 }
 
-func (f *FiveN1) scrapeWorker(page int) {
-	defer f.wg.Done()
+// scrapeWorker scrape pages until pages is closed
+func (f *FiveN1) scrapeWorker(ctx context.Context, run *scrapeRun, pages <-chan int) {
+	defer run.wg.Done()
 
+	for page := range pages {
+		f.scrapePage(ctx, run, page)
+	}
+}
+
+func (f *FiveN1) scrapePage(ctx context.Context, run *scrapeRun, page int) {
 	firstRow := strconv.Itoa(page * itemsPerPage)
-	response := f.request(f.queryURL + "&firstRow=" + firstRow)
+	response, err := f.request(ctx, run.queryURL+"&firstRow="+firstRow, run.cookieRegion)
+	if err != nil {
+		run.addError(err)
+		return
+	}
+
+	doc, err := newDocumentFromResponse(response)
+	if err != nil {
+		run.addError(err)
+		return
+	}
+
+	if err := expectPage(response, doc, "#content"); err != nil {
+		run.addError(err)
+		return
+	}
+
+	info := PageInfo{
+		Section: run.section,
+		Page:    page + 1,
+		Pages:   run.pages,
+		Records: run.records,
+	}
+	for _, rental := range parseRentHouse(doc) {
+		rental.Region = run.region
+		rental.Section = run.section
+		run.emit(rental, info)
+	}
+}
+
+// workers return how many workers are needed for n pages
+func (f *FiveN1) workers(n int) int {
+	if n < f.concurrency {
+		return n
+	}
 
-	doc := newDocumentFromResponse(response)
+	return f.concurrency
+}
 
-	f.parseRentHouse(doc)
+func (run *scrapeRun) addError(err error) {
+	run.mu.Lock()
+	run.errs = append(run.errs, err)
+	run.mu.Unlock()
 }
 
-func (f *FiveN1) parseRentHouse(doc *goquery.Document) {
+// parseRentHouse return every rental listed in a list page
+func parseRentHouse(doc *goquery.Document) (rentals Rentals) {
 	doc.Find("#content").Each(func(_ int, selector *goquery.Selection) {
 		selector.Find(".listInfo.clearfix").Each(func(item int, listInfo *goquery.Selection) {
 			rental := NewRental()
@@ -200,7 +499,7 @@ func (f *FiveN1) parseRentHouse(doc *goquery.Document) {
 
 				rental.OptionType = trimTextSpace(splitDescription[0])
 				rental.Ping = trimTextSpace(splitDescription[1])
-				rental.Floor = trimTextSpace(splitDescription[3])
+				rental.Floor = floorText(splitDescription[3])
 
 				// Rent House Address
 				address := stringReplacer(infoContent.Find(".lightBox").Eq(1).Text())
@@ -220,34 +519,49 @@ func (f *FiveN1) parseRentHouse(doc *goquery.Document) {
 			//	rental.IsNew = true
 			//})
 
+			rental.parseNumbers()
+
 			// Add rent house into list
-			f.rw.Lock()
-			f.rentals = append(f.rentals, *rental)
-			f.rw.Unlock()
+			rentals = append(rentals, *rental)
 		})
 	})
+
+	return rentals
 }
 
-func (f *FiveN1) setRegionCookie(region string) {
-	f.cookieRegion = &http.Cookie{
+// regionCookie tell 591 which region is queried
+func regionCookie(region string) *http.Cookie {
+	return &http.Cookie{
 		Name:  "urlJumpIp",
 		Value: region,
 	}
 }
 
-func (f *FiveN1) showQueryInfo() {
-	log.Printf("# Total Page: %3d | Total Record: %d\n", f.pages, f.records)
-	log.Printf("# Query URL: %s\n", f.queryURL)
+// detailCookie is the region cookie of the query listing the rental,
+// rentals saved without Region fall back to the region of their section
+func (f *FiveN1) detailCookie(r *Rental) *http.Cookie {
+	if r.Region != 0 {
+		return regionCookie(strconv.Itoa(r.Region))
+	}
+	if region, ok := sectionRegion(r.Section); ok {
+		return regionCookie(strconv.Itoa(region.Code))
+	}
+
+	return f.cookieRegion
 }
 
-func newDocumentFromResponse(response *http.Response) *goquery.Document {
-	doc, err := goquery.NewDocumentFromReader(response.Body)
+func (f *FiveN1) showQueryInfo(run *scrapeRun) {
+	f.logger.Printf("# Total Page: %3d | Total Record: %d\n", run.pages, run.records)
+	f.logger.Printf("# Query URL: %s\n", run.queryURL)
+}
+
+func newDocumentFromResponse(response *Response) (*goquery.Document, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(response.Body))
 	if err != nil {
-		log.Fatal(err)
+		return nil, &ParseError{URL: response.URL, Err: err}
 	}
-	defer response.Body.Close()
 
-	return doc
+	return doc, nil
 }
 
 func stringReplacer(text string) string {
@@ -260,6 +574,11 @@ func trimTextSpace(s string) string {
 	return strings.Fields(s)[0]
 }
 
+// floorText remove spaces of a floor, pangu space "樓層：頂樓加蓋/5" into "樓層：頂樓加蓋 / 5"
+func floorText(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
 func fillDescription(s []string) []string {
 	s = append(s, s[2])
 	s[2] = "沒有格局說明"
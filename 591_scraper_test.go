@@ -1,11 +1,17 @@
 package scraper
 
 import (
+	"context"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -38,7 +44,7 @@ func TestFiveN1_ScrapeList(t *testing.T) {
 
 		scraper := NewFiveN1()
 
-		_ = scraper.ScrapeRentals(query)
+		_, _ = scraper.ScrapeRentals(query)
 	})
 
 	t.Run("scrape url with 120 items", func(t *testing.T) {
@@ -49,11 +55,17 @@ func TestFiveN1_ScrapeList(t *testing.T) {
 		}
 
 		scraper := NewFiveN1()
-		rentals := scraper.ScrapeRentals(query)
+		rentals, err := scraper.ScrapeRentals(query)
 
-		assert.Equal(t, 120, scraper.records)
-		assert.Equal(t, 4, scraper.pages)
+		assert.Nil(t, err)
 		assert.Equal(t, 120, len(rentals))
+
+		run := &scrapeRun{queryURL: server.URL + "/?", cookieRegion: regionCookie("1"), emit: func(Rental, PageInfo) {}}
+		scraper.scrapeSection(context.Background(), run)
+
+		assert.Nil(t, run.errs)
+		assert.Equal(t, 120, run.records)
+		assert.Equal(t, 4, run.pages)
 	})
 
 	t.Run("scrape url with 333 items", func(t *testing.T) {
@@ -65,16 +77,14 @@ func TestFiveN1_ScrapeList(t *testing.T) {
 			_, _ = w.Write(html)
 		}))
 		defer server.Close()
-		query := &Query{
-			RootURL: server.URL + "/?",
-		}
+		run := &scrapeRun{queryURL: server.URL + "/?", cookieRegion: regionCookie("1"), emit: func(Rental, PageInfo) {}}
 
 		scraper := NewFiveN1()
+		scraper.scrapeSection(context.Background(), run)
 
-		_ = scraper.ScrapeRentals(query)
-
-		assert.Equal(t, 333, scraper.records)
-		assert.Equal(t, 12, scraper.pages)
+		assert.Nil(t, run.errs)
+		assert.Equal(t, 333, run.records)
+		assert.Equal(t, 12, run.pages)
 	})
 
 	t.Run("scrape to Rentals", func(t *testing.T) {
@@ -100,86 +110,108 @@ func TestFiveN1_ScrapeList(t *testing.T) {
 		}
 
 		f := NewFiveN1()
-		gotRentals := f.ScrapeRentals(query)
+		gotRentals, err := f.ScrapeRentals(query)
+		assert.Nil(t, err)
 
 		wantRentals := Rentals{
 			Rental{
-				Title:      "稀有花園別墅⭐別墅透天⭐雙平車⭐可寵",
-				URL:        "https://rent.591.com.tw/rent-detail-9538360.html",
-				Address:    "近好事多南區西區向上路黎明路永春東路南屯區 - 惠中路三段",
-				OptionType: "整層住家",
-				Ping:       "128",
-				Floor:      "樓層：整棟",
-				Price:      "48,000 元 / 月",
-				ID:         "R9538360",
-				PostBy:     "代理人 高先生",
-				Phone:      "",
-				Section:    "98",
+				Title:       "稀有花園別墅⭐別墅透天⭐雙平車⭐可寵",
+				URL:         "https://rent.591.com.tw/rent-detail-9538360.html",
+				Address:     "近好事多南區西區向上路黎明路永春東路南屯區 - 惠中路三段",
+				OptionType:  "整層住家",
+				Ping:        "128",
+				Floor:       "樓層：整棟/4",
+				Price:       "48,000 元 / 月",
+				ID:          "R9538360",
+				PostBy:      "代理人 高先生",
+				Phone:       "",
+				Section:     "98",
+				PriceValue:  48000,
+				PingValue:   128,
+				TotalFloors: 4,
 			},
 			Rental{
-				Title:      "中興大學賺錢店面",
-				URL:        "https://rent.591.com.tw/rent-detail-9484376.html",
-				Address:    "賺錢住店南區 - 建成路 1727 號",
-				OptionType: "整層住家",
-				Ping:       "50.8",
-				Floor:      "樓層：1/12",
-				Price:      "50,000 元 / 月",
-				ID:         "R9484376",
-				PostBy:     "仲介 李士豪",
-				Phone:      "",
-				Section:    "98",
+				Title:       "中興大學賺錢店面",
+				URL:         "https://rent.591.com.tw/rent-detail-9484376.html",
+				Address:     "賺錢住店南區 - 建成路 1727 號",
+				OptionType:  "整層住家",
+				Ping:        "50.8",
+				Floor:       "樓層：1/12",
+				Price:       "50,000 元 / 月",
+				ID:          "R9484376",
+				PostBy:      "仲介 李士豪",
+				Phone:       "",
+				Section:     "98",
+				PriceValue:  50000,
+				PingValue:   50.8,
+				FloorValue:  1,
+				TotalFloors: 12,
 			},
 			Rental{
-				Title:      "稀有花園別墅⭐別墅透天⭐雙平車⭐可寵",
-				URL:        "https://rent.591.com.tw/rent-detail-9538360.html",
-				Address:    "近好事多南區西區向上路黎明路永春東路南屯區 - 惠中路三段",
-				OptionType: "整層住家",
-				Ping:       "128",
-				Floor:      "樓層：整棟",
-				Price:      "48,000 元 / 月",
-				ID:         "R9538360",
-				PostBy:     "代理人 高先生",
-				Phone:      "",
-				Section:    "99",
+				Title:       "稀有花園別墅⭐別墅透天⭐雙平車⭐可寵",
+				URL:         "https://rent.591.com.tw/rent-detail-9538360.html",
+				Address:     "近好事多南區西區向上路黎明路永春東路南屯區 - 惠中路三段",
+				OptionType:  "整層住家",
+				Ping:        "128",
+				Floor:       "樓層：整棟/4",
+				Price:       "48,000 元 / 月",
+				ID:          "R9538360",
+				PostBy:      "代理人 高先生",
+				Phone:       "",
+				Section:     "99",
+				PriceValue:  48000,
+				PingValue:   128,
+				TotalFloors: 4,
 			},
 			Rental{
-				Title:      "中興大學賺錢店面",
-				URL:        "https://rent.591.com.tw/rent-detail-9484376.html",
-				Address:    "賺錢住店南區 - 建成路 1727 號",
-				OptionType: "整層住家",
-				Ping:       "50.8",
-				Floor:      "樓層：1/12",
-				Price:      "50,000 元 / 月",
-				ID:         "R9484376",
-				PostBy:     "仲介 李士豪",
-				Phone:      "",
-				Section:    "99",
+				Title:       "中興大學賺錢店面",
+				URL:         "https://rent.591.com.tw/rent-detail-9484376.html",
+				Address:     "賺錢住店南區 - 建成路 1727 號",
+				OptionType:  "整層住家",
+				Ping:        "50.8",
+				Floor:       "樓層：1/12",
+				Price:       "50,000 元 / 月",
+				ID:          "R9484376",
+				PostBy:      "仲介 李士豪",
+				Phone:       "",
+				Section:     "99",
+				PriceValue:  50000,
+				PingValue:   50.8,
+				FloorValue:  1,
+				TotalFloors: 12,
 			},
 			Rental{
-				Title:      "稀有花園別墅⭐別墅透天⭐雙平車⭐可寵",
-				URL:        "https://rent.591.com.tw/rent-detail-9538360.html",
-				Address:    "近好事多南區西區向上路黎明路永春東路南屯區 - 惠中路三段",
-				OptionType: "整層住家",
-				Ping:       "128",
-				Floor:      "樓層：整棟",
-				Price:      "48,000 元 / 月",
-				ID:         "R9538360",
-				PostBy:     "代理人 高先生",
-				Phone:      "",
-				Section:    "100",
+				Title:       "稀有花園別墅⭐別墅透天⭐雙平車⭐可寵",
+				URL:         "https://rent.591.com.tw/rent-detail-9538360.html",
+				Address:     "近好事多南區西區向上路黎明路永春東路南屯區 - 惠中路三段",
+				OptionType:  "整層住家",
+				Ping:        "128",
+				Floor:       "樓層：整棟/4",
+				Price:       "48,000 元 / 月",
+				ID:          "R9538360",
+				PostBy:      "代理人 高先生",
+				Phone:       "",
+				Section:     "100",
+				PriceValue:  48000,
+				PingValue:   128,
+				TotalFloors: 4,
 			},
 			Rental{
-				Title:      "中興大學賺錢店面",
-				URL:        "https://rent.591.com.tw/rent-detail-9484376.html",
-				Address:    "賺錢住店南區 - 建成路 1727 號",
-				OptionType: "整層住家",
-				Ping:       "50.8",
-				Floor:      "樓層：1/12",
-				Price:      "50,000 元 / 月",
-				ID:         "R9484376",
-				PostBy:     "仲介 李士豪",
-				Phone:      "",
-				Section:    "100",
+				Title:       "中興大學賺錢店面",
+				URL:         "https://rent.591.com.tw/rent-detail-9484376.html",
+				Address:     "賺錢住店南區 - 建成路 1727 號",
+				OptionType:  "整層住家",
+				Ping:        "50.8",
+				Floor:       "樓層：1/12",
+				Price:       "50,000 元 / 月",
+				ID:          "R9484376",
+				PostBy:      "仲介 李士豪",
+				Phone:       "",
+				Section:     "100",
+				PriceValue:  50000,
+				PingValue:   50.8,
+				FloorValue:  1,
+				TotalFloors: 12,
 			},
 		}
 
@@ -240,8 +272,9 @@ func TestFiveN1_ScrapeDetail(t *testing.T) {
 		}
 
 		scraper := NewFiveN1()
-		_ = scraper.ScrapeRentalDetail(rental)
+		err := scraper.ScrapeRentalDetail(rental)
 
+		assert.Nil(t, err)
 		assert.Equal(t, "0980-240-200", rental.Phone, "rental.Phone not equal")
 		assert.Equal(t, "6房3廳4衛4陽台", rental.Layout, "rental.Layout not equal")
 		//need a better fixture here
@@ -270,14 +303,49 @@ func TestFiveN1_ScrapeDetail(t *testing.T) {
 		}
 
 		scraper := NewFiveN1()
-		_ = scraper.ScrapeRentalDetail(rental)
+		err := scraper.ScrapeRentalDetail(rental)
 
+		assert.Nil(t, err)
 		assert.Equal(t, "0986-851-077 轉 1397162", rental.Phone, "rental.Phone not equal")
 		assert.Equal(t, "", rental.Layout, "rental.Layout not equal")
 		assert.Equal(t, "豐邑閱文心", rental.Community, "rental.Community not equal")
 	})
 }
 
+func TestFiveN1_ScrapeDetailRegionCookie(t *testing.T) {
+	var gotRegion string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("urlJumpIp")
+		assert.Nil(t, err)
+		gotRegion = cookie.Value
+
+		html, _ := ioutil.ReadFile("test_fixture/591_detail.html")
+		_, _ = w.Write(html)
+	}))
+	defer svr.Close()
+
+	tests := []struct {
+		region     int
+		section    string
+		wantRegion string
+	}{
+		{8, "中區", "8"},
+		{8, "", "8"},
+		{0, "104", "8"},
+		{0, "西屯區", "8"},
+		{0, "1", "1"},
+		{0, "東區", "1"}, // shared by several regions
+		{0, "", "1"},
+	}
+	for _, tt := range tests {
+		f := NewFiveN1(WithDelay(0))
+		err := f.ScrapeRentalDetail(&Rental{URL: svr.URL + "/rent-detail-9538360.html", Region: tt.region, Section: tt.section})
+
+		assert.Nil(t, err)
+		assert.Equal(t, tt.wantRegion, gotRegion, "region %d section %q", tt.region, tt.section)
+	}
+}
+
 func TestFiveN1_ScrapeRentalsDetail(t *testing.T) {
 	const rentalDetailPath = "/rent-detail-9538360.html"
 	rental := Rental{
@@ -292,10 +360,6 @@ func TestFiveN1_ScrapeRentalsDetail(t *testing.T) {
 		Phone:      "",
 		Section:    "99",
 	}
-	rentals := Rentals{
-		rental,
-		rental,
-	}
 
 	t.Run("update all rental.Phone", func(t *testing.T) {
 		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -308,11 +372,356 @@ func TestFiveN1_ScrapeRentalsDetail(t *testing.T) {
 		defer svr.Close()
 
 		rental.URL = svr.URL + rentalDetailPath
+		rentals := Rentals{
+			rental,
+			rental,
+		}
 
 		scraper := NewFiveN1()
-		scraper.ScrapeRentalsDetail(rentals)
+		err := scraper.ScrapeRentalsDetail(rentals)
 
+		assert.Nil(t, err)
 		assert.Equal(t, "0980-240-200", rentals[0].Phone)
 		assert.Equal(t, "0980-240-200", rentals[1].Phone)
 	})
+
+	t.Run("collect errors and keep scraping the others", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/gone.html" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			html, _ := ioutil.ReadFile("test_fixture/591_detail.html")
+			_, _ = w.Write(html)
+		}))
+		defer svr.Close()
+
+		gone := rental
+		gone.URL = svr.URL + "/gone.html"
+		found := rental
+		found.URL = svr.URL + rentalDetailPath
+		rentals := Rentals{gone, found}
+
+		scraper := NewFiveN1()
+		err := scraper.ScrapeRentalsDetail(rentals)
+
+		var errs Errors
+		assert.True(t, errors.As(err, &errs))
+		assert.Len(t, errs, 1)
+		var detailErr *DetailError
+		assert.True(t, errors.As(err, &detailErr))
+		assert.Equal(t, 0, detailErr.Index)
+		assert.Equal(t, gone.URL, detailErr.URL)
+		var statusErr *StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+		assert.Equal(t, "", rentals[0].Phone)
+		assert.Equal(t, "0980-240-200", rentals[1].Phone)
+	})
+}
+
+func TestFiveN1_ScrapeRentalsDetailConcurrently(t *testing.T) {
+	t.Run("keep rentals in order", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fixture := "test_fixture/591_detail.html"
+			if r.URL.Path == "/without_layout.html" {
+				fixture = "test_fixture/591_detail_without_layout.html"
+			}
+			time.Sleep(10 * time.Millisecond)
+			html, _ := ioutil.ReadFile(fixture)
+			_, _ = w.Write(html)
+		}))
+		defer svr.Close()
+
+		var rentals Rentals
+		for i := 0; i < 20; i++ {
+			path := "/detail.html"
+			if i%2 == 1 {
+				path = "/without_layout.html"
+			}
+			rentals = append(rentals, Rental{ID: strconv.Itoa(i), URL: svr.URL + path})
+		}
+
+		var reported []Progress
+		scraper := NewFiveN1(WithConcurrency(4), WithProgress(ProgressFunc(func(p Progress) {
+			reported = append(reported, p)
+		})))
+		err := scraper.ScrapeRentalsDetail(rentals)
+
+		assert.Nil(t, err)
+		for i, rental := range rentals {
+			assert.Equal(t, strconv.Itoa(i), rental.ID)
+			if i%2 == 1 {
+				assert.Equal(t, "0986-851-077 轉 1397162", rental.Phone)
+			} else {
+				assert.Equal(t, "0980-240-200", rental.Phone)
+			}
+		}
+
+		assert.Len(t, reported, 20)
+		last := reported[len(reported)-1]
+		assert.Equal(t, 20, last.Done)
+		assert.Equal(t, 20, last.Total)
+		assert.Equal(t, 0, last.Failed)
+		assert.Equal(t, time.Duration(0), last.ETA)
+	})
+
+	t.Run("report failures", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer svr.Close()
+
+		rentals := Rentals{{URL: svr.URL}, {URL: svr.URL}, {URL: svr.URL}}
+
+		var last Progress
+		scraper := NewFiveN1(WithProgress(ProgressFunc(func(p Progress) {
+			last = p
+		})))
+		err := scraper.ScrapeRentalsDetail(rentals)
+
+		var errs Errors
+		assert.True(t, errors.As(err, &errs))
+		assert.Len(t, errs, 3)
+		for i, err := range errs {
+			assert.Equal(t, i, err.(*DetailError).Index)
+		}
+		assert.Equal(t, Progress{Done: 3, Failed: 3, Total: 3, Elapsed: last.Elapsed}, last)
+	})
+}
+
+func TestFiveN1_ScrapeErrors(t *testing.T) {
+	t.Run("return partial rentals with failed pages", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("firstRow") == "30" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			item120Handler(w, r)
+		}))
+		defer server.Close()
+		query := &Query{
+			RootURL: server.URL + "/?",
+		}
+
+		scraper := NewFiveN1(WithRetryPolicy(fastRetryPolicy()))
+		rentals, err := scraper.ScrapeRentals(query)
+
+		var errs Errors
+		assert.True(t, errors.As(err, &errs))
+		assert.Len(t, errs, 1)
+		var statusErr *StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+		assert.Equal(t, 90, len(rentals))
+	})
+
+	t.Run("return RequestError when server unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(item120Handler))
+		server.Close()
+
+		rental := &Rental{URL: server.URL + "/rent-detail-9538360.html"}
+
+		scraper := NewFiveN1()
+		err := scraper.ScrapeRentalDetail(rental)
+
+		var requestErr *RequestError
+		assert.True(t, errors.As(err, &requestErr))
+		assert.Equal(t, rental.URL, requestErr.URL)
+	})
+}
+
+func TestFiveN1_ScrapeContext(t *testing.T) {
+	t.Run("stop scraping sections once context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var gotQuerySection []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			section := r.URL.Query().Get("section")
+			// cancel once the first section is done
+			if section == "99" {
+				cancel()
+				return
+			}
+			gotQuerySection = append(gotQuerySection, section)
+
+			html, _ := ioutil.ReadFile("test_fixture/591with2items.html")
+			_, _ = w.Write(html)
+		}))
+		defer server.Close()
+
+		query := &Query{
+			RootURL: server.URL + "/?",
+			Section: "98,99,100",
+		}
+
+		f := NewFiveN1()
+		rentals, err := f.ScrapeRentalsContext(ctx, query)
+
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, []string{"98", "98"}, gotQuerySection)
+		assert.Equal(t, 2, len(rentals))
+	})
+
+	t.Run("leave rentals untouched once context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		requested := 0
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			if requested > 1 {
+				cancel()
+				return
+			}
+			html, _ := ioutil.ReadFile("test_fixture/591_detail.html")
+			_, _ = w.Write(html)
+		}))
+		defer svr.Close()
+
+		rentals := Rentals{
+			{URL: svr.URL + "/rent-detail-9538360.html"},
+			{URL: svr.URL + "/rent-detail-9538360.html"},
+		}
+
+		f := NewFiveN1(WithConcurrency(1))
+		err := f.ScrapeRentalsDetailContext(ctx, rentals)
+
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, 2, requested)
+		assert.Equal(t, "0980-240-200", rentals[0].Phone)
+		assert.Equal(t, "", rentals[1].Phone)
+	})
+}
+
+func TestFiveN1_ScrapeConcurrency(t *testing.T) {
+	t.Run("never request more pages than concurrency at once", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			item120Handler(w, r)
+		}))
+		defer server.Close()
+		query := &Query{
+			RootURL: server.URL + "/?",
+		}
+
+		scraper := NewFiveN1(WithConcurrency(2))
+		rentals, err := scraper.ScrapeRentals(query)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 120, len(rentals))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+	})
+
+	t.Run("share rate limit between list and detail pages", func(t *testing.T) {
+		var requested int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requested, 1)
+			html, _ := ioutil.ReadFile("test_fixture/591with2items.html")
+			_, _ = w.Write(html)
+		}))
+		defer server.Close()
+		query := &Query{
+			RootURL: server.URL + "/?",
+		}
+
+		scraper := NewFiveN1(WithRateLimit(20, 1))
+
+		start := time.Now()
+		rentals, err := scraper.ScrapeRentals(query)
+		assert.Nil(t, err)
+		for i := range rentals {
+			rentals[i].URL = server.URL + "/rent-detail.html"
+		}
+		_ = scraper.ScrapeRentalsDetail(rentals)
+
+		// 2 list requests and 2 detail requests, only the first one is free
+		assert.Equal(t, int32(4), atomic.LoadInt32(&requested))
+		assert.True(t, time.Since(start) >= 140*time.Millisecond, "took %s", time.Since(start))
+	})
+}
+
+func TestFiveN1_ScrapeConcurrently(t *testing.T) {
+	newRegionServer := func(t *testing.T, region string, fixture string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotCookie, err := r.Cookie("urlJumpIp")
+			assert.Nil(t, err)
+			assert.Equal(t, region, gotCookie.Value)
+
+			html, _ := ioutil.ReadFile(fixture)
+			_, _ = w.Write(html)
+		}))
+	}
+
+	taipei := newRegionServer(t, "1", "test_fixture/591with120items.html")
+	defer taipei.Close()
+	taichung := newRegionServer(t, "8", "test_fixture/591with2items.html")
+	defer taichung.Close()
+
+	scraper := NewFiveN1()
+
+	var wg sync.WaitGroup
+	var taipeiRentals, taichungRentals Rentals
+	var taipeiErr, taichungErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		taipeiRentals, taipeiErr = scraper.ScrapeRentals(&Query{RootURL: taipei.URL + "/?", Region: 1, Section: "1,2"})
+	}()
+	go func() {
+		defer wg.Done()
+		taichungRentals, taichungErr = scraper.ScrapeRentals(&Query{RootURL: taichung.URL + "/?", Region: 8, Section: "98"})
+	}()
+	wg.Wait()
+
+	assert.Nil(t, taipeiErr)
+	assert.Nil(t, taichungErr)
+	assert.Equal(t, 240, len(taipeiRentals))
+	assert.Equal(t, 2, len(taichungRentals))
+	for _, rental := range taichungRentals {
+		assert.Equal(t, 8, rental.Region)
+		assert.Equal(t, "98", rental.Section)
+	}
+}
+
+func TestFiveN1_ScrapePreset(t *testing.T) {
+	t.Run("scrape a preset twice without mutating it", func(t *testing.T) {
+		var gotQuerySection []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotQuerySection = append(gotQuerySection, r.URL.Query().Get("section"))
+
+			html, _ := ioutil.ReadFile("test_fixture/591with2items.html")
+			_, _ = w.Write(html)
+		}))
+		defer server.Close()
+
+		query := QueryTaipei()
+		query.RootURL = server.URL + "/?"
+		wantSection := query.Section
+
+		scraper := NewFiveN1()
+		first, err := scraper.ScrapeRentals(query)
+		assert.Nil(t, err)
+		second, err := scraper.ScrapeRentals(query)
+		assert.Nil(t, err)
+
+		assert.Equal(t, wantSection, query.Section)
+		assert.Equal(t, wantSection, QueryTaipei().Section)
+		assert.Equal(t, 24, len(first))
+		assert.Equal(t, 24, len(second))
+		// request twice per section, twice per run
+		assert.Equal(t, 48, len(gotQuerySection))
+		assert.Equal(t, "12", gotQuerySection[len(gotQuerySection)-1])
+	})
 }
@@ -388,4 +388,9 @@ var sectionDict = map[string]string{
 	"367": "玉里鎮",
 	"368": "卓溪鄉",
 	"369": "富里鄉",
+	"370": "東區",
+	"371": "北區",
+	"372": "香山區",
+	"373": "東區",
+	"374": "西區",
 }
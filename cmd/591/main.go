@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	scraper "web_scraper"
+)
+
+type command struct {
+	name  string
+	usage string // arguments after flags
+	short string
+	run   func(fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"scrape", "", "scrape rentals of a search and save them", runScrape},
+	{"profile", "[profile ...]", "scrape searches saved in a profiles file, list them without profile", runProfile},
+	{"detail", "rentals.json|.jsonl|.xlsx", "scrape details of saved rentals", runDetail},
+	{"export", "rentals.json|.jsonl|.xlsx ...", "filter, sort and save saved rentals in other formats", runExport},
+	{"diff", "old.json|.jsonl|.xlsx new.json|.jsonl|.xlsx", "compare two saved runs", runDiff},
+	{"regions", "[region]", "list regions, or sections of a region", runRegions},
+}
+
+// errIncomplete is returned after saving rentals when 591 blocked us or served unexpected pages
+var errIncomplete = errors.New("591 blocked us or served unexpected pages, rentals may be incomplete")
+
+// 591 scrape rentals of rent.591.com.tw, ex:
+//
+//	591 scrape -region 台中市 -section 98,99,100 -kind 0 -rentprice 0,20000 -formats xlsx,csv
+//	591 profile -config profiles.yaml taichung taipei
+//	591 export -filter "ping=8," -sort price -formats csv 2020-07-01-台中市.json
+//	591 diff 2020-07-01-台中市.json 2020-07-02-台中市.json
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		usage()
+		return
+	}
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+
+		fs := flag.NewFlagSet("591 "+c.name, flag.ExitOnError)
+		fs.Usage = func() {
+			fmt.Fprintf(fs.Output(), "usage: %s\n%s\n\nflags:\n", strings.TrimSpace("591 "+c.name+" [flags] "+c.usage), c.short)
+			fs.PrintDefaults()
+		}
+		err := c.run(fs, os.Args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: 591 <command> [flags] [arguments]\n\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.short)
+	}
+	fmt.Fprintln(os.Stderr, "\nrun \"591 <command> -h\" for flags of a command")
+}
+
+// parseArgs parse flags and check the number of arguments is between min and max, max < 0 means no limit
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) error {
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() < min || max >= 0 && fs.NArg() > max {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	return nil
+}
+
+func runDiff(fs *flag.FlagSet, args []string) error {
+	out := outputFlags(fs, "diff")
+	err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	err = out.parse()
+	if err != nil {
+		return err
+	}
+
+	old, err := scraper.LoadRentals(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	new, err := scraper.LoadRentals(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	diff := scraper.Diff(old, new)
+	for _, rental := range diff.Added {
+		log.Printf("+ %s %s %s %s", rental.ID, rental.Price, rental.Title, rental.URL)
+	}
+	for _, rental := range diff.Removed {
+		log.Printf("- %s %s %s %s", rental.ID, rental.Price, rental.Title, rental.URL)
+	}
+	for _, change := range diff.Changed {
+		log.Printf("~ %s %s -> %s | %s -> %s %s", change.New.ID, change.Old.Price, change.New.Price, change.Old.Title, change.New.Title, change.New.URL)
+	}
+	log.Printf("added: %d | removed: %d | changed: %d", len(diff.Added), len(diff.Removed), len(diff.Changed))
+
+	filename, err := out.filename(map[string]string{"input": inputName(fs.Arg(1))})
+	if err != nil {
+		return err
+	}
+	err = diff.SaveAsJSON(filename + ".json")
+	if err != nil {
+		return err
+	}
+	return diff.SaveAsXLSX(filename+".xlsx", out.columns...)
+}
+
+func runRegions(fs *flag.FlagSet, args []string) error {
+	err := parseArgs(fs, args, 0, 1)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		for _, r := range scraper.Regions() {
+			fmt.Printf("%3d %s\t%d sections\n", r.Code, r.Name, len(r.Sections))
+		}
+		return nil
+	}
+
+	r, err := scraper.FindRegion(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d %s\n", r.Code, r.Name)
+	for _, code := range r.Sections {
+		fmt.Printf("%5s %s\n", code, scraper.SectionName(code))
+	}
+	return nil
+}
+
+// cancelOnInterrupt stop scraping on Ctrl-C, rentals scraped so far are still saved
+func cancelOnInterrupt(cancel context.CancelFunc) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	<-interrupt
+	log.Println("interrupted, saving rentals scraped so far")
+	cancel()
+}
+
+// unexpectedPage report whether 591 blocked us or served something other than rentals
+func unexpectedPage(err error) bool {
+	return errors.Is(err, scraper.ErrBlocked) || errors.Is(err, scraper.ErrUnexpectedPage)
+}
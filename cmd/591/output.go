@@ -0,0 +1,182 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	scraper "web_scraper"
+)
+
+// output is where and how rentals are saved
+type output struct {
+	dir        string
+	name       string
+	columnSpec string
+	formats    string
+	bySection  bool
+
+	columns []scraper.Column // parsed from columnSpec
+}
+
+// outputFlags register -out, -name and -columns, name is the default filename template
+func outputFlags(fs *flag.FlagSet, name string) *output {
+	o := &output{}
+	fs.StringVar(&o.dir, "out", ".", "directory of output files, created when missing")
+	fs.StringVar(&o.name, "name", name, "filename without extension, {date}, {time}, {region}, {profile} and {input} are replaced")
+	fs.StringVar(&o.columnSpec, "columns", "", "columns of xlsx, csv and jsonl separated by \",\", ex: title,price,phone,url")
+	return o
+}
+
+// formatFlags register -formats and -by-section for commands saving rentals
+func (o *output) formatFlags(fs *flag.FlagSet, formats string) {
+	fs.StringVar(&o.formats, "formats", formats, "output formats separated by \",\": xlsx, csv, jsonl and json")
+	fs.BoolVar(&o.bySection, "by-section", false, "save xlsx with a sheet per section and a summary sheet")
+}
+
+// parse check -columns and -formats before anything is scraped
+func (o *output) parse() error {
+	var err error
+	o.columns, err = scraper.ParseColumns(o.columnSpec)
+	if err != nil {
+		return err
+	}
+
+	for _, format := range o.formatList() {
+		switch format {
+		case "xlsx", "csv", "jsonl", "json":
+		default:
+			return fmt.Errorf("unknown format %q, valid formats: xlsx, csv, jsonl and json", format)
+		}
+	}
+
+	return nil
+}
+
+func (o *output) formatList() []string {
+	var formats []string
+	for _, format := range strings.Split(o.formats, ",") {
+		if format = strings.TrimSpace(format); format != "" {
+			formats = append(formats, format)
+		}
+	}
+
+	return formats
+}
+
+// filename fill the -name template with the date, time and vars, and create -out
+func (o *output) filename(vars map[string]string) (string, error) {
+	now := time.Now()
+	pairs := []string{"{date}", now.Format("2006-01-02"), "{time}", now.Format("150405")}
+	for key, value := range vars {
+		pairs = append(pairs, "{"+key+"}", value)
+	}
+
+	name := strings.NewReplacer(pairs...).Replace(o.name)
+	if i := strings.Index(name, "{"); i >= 0 && strings.Contains(name[i:], "}") {
+		return "", fmt.Errorf("unknown placeholder in -name %q", o.name)
+	}
+
+	err := os.MkdirAll(o.dir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("create %s error %v", o.dir, err)
+	}
+
+	return filepath.Join(o.dir, name), nil
+}
+
+// save save rentals as filename with extension of every -formats,
+// a failed format doesn't stop the others and every error is returned together
+func (o *output) save(rentals scraper.Rentals, filename string) error {
+	var errs scraper.Errors
+	for _, format := range o.formatList() {
+		var err error
+		switch format {
+		case "xlsx":
+			if o.bySection {
+				err = rentals.SaveAsXLSXBySection(filename+".xlsx", o.columns...)
+			} else {
+				err = rentals.SaveAsXLSX(filename+".xlsx", o.columns...)
+			}
+		case "csv":
+			err = rentals.SaveAsCSV(filename+".csv", o.columns...)
+		case "jsonl":
+			err = rentals.SaveAsJSONL(filename+".jsonl", o.columns...)
+		case "json":
+			err = rentals.SaveAsJSON(filename + ".json")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("save %s.%s error %v", filename, format, err))
+			continue
+		}
+		log.Printf("saved %d rentals to %s.%s", len(rentals), filename, format)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// inputName is the base name of a file without extension, ex: "2020-07-01" of "data/2020-07-01.json"
+func inputName(filename string) string {
+	base := filepath.Base(filename)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// rentalFlags is how rentals are cleaned up before they are saved
+type rentalFlags struct {
+	filterSpec  string
+	sortSpec    string
+	keepReposts bool
+
+	filters  []scraper.Filter
+	sortKeys []scraper.SortKey
+}
+
+// listFlags register -filter, -sort and -keep-reposts
+func listFlags(fs *flag.FlagSet) *rentalFlags {
+	l := &rentalFlags{}
+	fs.StringVar(&l.filterSpec, "filter", "", `keep rentals matching every filter, ex: "price=,20000;ping=8,;postby=屋主"`)
+	fs.StringVar(&l.sortSpec, "sort", "", `sort rentals by keys, "-" for descending, ex: "section,-ping,price"`)
+	fs.BoolVar(&l.keepReposts, "keep-reposts", false, "only remove rentals with the same id, keep the same unit reposted under new ids")
+	return l
+}
+
+func (l *rentalFlags) parse() error {
+	var err error
+	l.filters, err = scraper.ParseFilters(l.filterSpec)
+	if err != nil {
+		return err
+	}
+	l.sortKeys, err = scraper.ParseSortKeys(l.sortSpec)
+	return err
+}
+
+// dedupe remove rentals scraped twice and units reposted under new ids unless -keep-reposts
+func (l *rentalFlags) dedupe(rentals scraper.Rentals) scraper.Rentals {
+	keys := []scraper.DedupeKey{scraper.SameID}
+	if !l.keepReposts {
+		keys = append(keys, scraper.SameUnit)
+	}
+
+	deduped, merges := rentals.Dedupe(keys...)
+	for _, merge := range merges {
+		for _, rental := range merge.Merged {
+			log.Printf("# Dedupe: %s merged into %s %s", rental.URL, merge.Kept.ID, merge.Kept.Title)
+		}
+	}
+
+	return deduped
+}
+
+// apply filter and sort rentals
+func (l *rentalFlags) apply(rentals scraper.Rentals) scraper.Rentals {
+	rentals = rentals.Filter(l.filters...)
+	rentals.Sort(l.sortKeys...)
+	return rentals
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	scraper "web_scraper"
+)
+
+func runProfile(fs *flag.FlagSet, args []string) error {
+	config := fs.String("config", "profiles.yaml", "profiles file, .yaml, .yml, .toml or .json")
+	all := fs.Bool("all", false, "run every profile")
+	client := newScraperFlags(fs)
+	keepReposts := fs.Bool("keep-reposts", false, "only remove rentals with the same id, keep the same unit reposted under new ids")
+	dbFile := fs.String("db", "", "sqlite database keeping history of rentals, ex: rentals.db")
+	err := parseArgs(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+
+	profiles, err := scraper.LoadProfiles(*config)
+	if err != nil {
+		return err
+	}
+	names := fs.Args()
+	if *all {
+		names = profiles.Names()
+	}
+	if len(names) == 0 {
+		return listProfiles(profiles)
+	}
+	selected, err := profiles.Get(names...)
+	if err != nil {
+		return err
+	}
+
+	var searches []search
+	for _, p := range selected {
+		se, err := profileSearch(p, *keepReposts)
+		if err != nil {
+			return err
+		}
+		searches = append(searches, se)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cancelOnInterrupt(cancel)
+
+	s, err := client.newFiveN1()
+	if err != nil {
+		return err
+	}
+
+	// every profile of a run is seen at the same time
+	seenAt := time.Now()
+	var failed []string
+	for i, se := range searches {
+		if ctx.Err() != nil {
+			break
+		}
+		log.Printf("# Profile: %s", selected[i].Name)
+		err := se.run(ctx, s, *dbFile, seenAt)
+		if err != nil {
+			log.Printf("profile %s error: %v", selected[i].Name, err)
+			failed = append(failed, selected[i].Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("profiles failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// profileSearch create the search of a profile, with its filters, sort and output
+func profileSearch(p scraper.Profile, keepReposts bool) (search, error) {
+	q, err := p.Query()
+	if err != nil {
+		return search{}, err
+	}
+	region, err := scraper.FindRegion(p.Region)
+	if err != nil {
+		return search{}, err
+	}
+
+	list := &rentalFlags{
+		filterSpec:  strings.Join(p.Filters, ";"),
+		sortSpec:    strings.Join(p.Sort, ","),
+		keepReposts: keepReposts,
+	}
+	out := &output{
+		dir:        p.Output.Dir,
+		name:       p.Output.Name,
+		columnSpec: strings.Join(p.Output.Columns, ","),
+		formats:    strings.Join(p.Output.Formats, ","),
+		bySection:  p.Output.BySection,
+	}
+	if out.dir == "" {
+		out.dir = "."
+	}
+	if out.name == "" {
+		out.name = "{date}-{profile}"
+	}
+	if out.formats == "" {
+		out.formats = "xlsx"
+	}
+	for _, parse := range []func() error{list.parse, out.parse} {
+		if err := parse(); err != nil {
+			return search{}, fmt.Errorf("profile %q error %v", p.Name, err)
+		}
+	}
+
+	filename, err := out.filename(map[string]string{"profile": p.Name, "region": region.Name})
+	if err != nil {
+		return search{}, fmt.Errorf("profile %q error %v", p.Name, err)
+	}
+
+	return search{query: q, scope: p.Name, detail: p.ScrapeDetail(), list: list, out: out, filename: filename}, nil
+}
+
+// listProfiles print every profile with its query url
+func listProfiles(profiles scraper.Profiles) error {
+	for _, name := range profiles.Names() {
+		q, err := profiles[name].Query()
+		if err != nil {
+			return err
+		}
+		url, err := q.URL()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\t%s\n", name, url)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	scraper "web_scraper"
+)
+
+// queryFlags is a Query set by flags named after parameters of 591 url, so a search on the site can be copied.
+type queryFlags struct {
+	query  *scraper.Query
+	region string
+
+	regionName string // set by parse
+}
+
+// newQueryFlags register a flag for every Query field with NewQuery as default,
+// except -kind and -rentprice which don't narrow the search unless set
+func newQueryFlags(fs *flag.FlagSet) *queryFlags {
+	q := &queryFlags{query: scraper.NewQuery()}
+	q.query.Section = ""
+	q.query.Kind = scraper.KindAny
+	q.query.RentPrice = ""
+
+	fs.StringVar(&q.region, "region", "1", "地區 code or name, see \"591 regions\"")
+	fs.StringVar(&q.query.Section, "section", q.query.Section, "鄉鎮 names or codes separated by \",\", every section of the region when empty, see \"591 regions <region>\"")
+	fs.IntVar((*int)(&q.query.Kind), "kind", int(q.query.Kind), "租屋類型 0: 不限, 1: 整層住家, 2: 獨立套房, 3: 分租套房, 4: 雅房, 8: 車位, 24: 其他")
+	fs.StringVar((*string)(&q.query.RentPrice), "rentprice", string(q.query.RentPrice), "租金 2: 5k-10k, 3: 10k-20k, 4: 20k-30k, or a range, ex: 0,10000")
+	fs.StringVar(&q.query.Area, "area", q.query.Area, "坪數 range, ex: 10,20")
+	fs.StringVar(&q.query.Order, "order", q.query.Order, "排序欄位 posttime or money")
+	fs.StringVar(&q.query.OrderType, "orderType", q.query.OrderType, "排序方式 desc or asc")
+	fs.IntVar((*int)(&q.query.Sex), "sex", int(q.query.Sex), "性別 0: 不限, 1: 男性, 2: 女性")
+	fs.StringVar(&q.query.HasImg, "hasimg", q.query.HasImg, "1: 有房屋照片")
+	fs.StringVar(&q.query.NotCover, "not_cover", q.query.NotCover, "1: 排除頂樓加蓋")
+	fs.StringVar(&q.query.Role, "role", q.query.Role, "1: 屋主刊登")
+	fs.StringVar((*string)(&q.query.Shape), "shape", string(q.query.Shape), "房屋類型 1: 公寓, 2: 電梯大樓, 3: 透天厝, 4: 別墅")
+	fs.StringVar((*string)(&q.query.Pattern), "pattern", string(q.query.Pattern), "格局 0: 不限, 1-4: 房數, 5: 五房以上")
+	fs.StringVar(&q.query.PatternMore, "patternMore", q.query.PatternMore, "多選格局, ex: 1,2,3")
+	fs.StringVar((*string)(&q.query.Floor), "floor", string(q.query.Floor), "樓層 range, ex: 0,1 for 1F, 2,6 for 2F to 6F, 12, for 12F and above")
+	fs.StringVar(&q.query.Option, "option", q.query.Option, "提供設備, ex: tv,cold,icebox,hotwater,naturalgas,four,broadband,washer,bed,wardrobe,sofa")
+	fs.StringVar(&q.query.Other, "other", q.query.Other, "其他條件, ex: cartplace,lift,balcony_1,cook,pet,tragoods,lease")
+	fs.StringVar(&q.query.RootURL, "root-url", q.query.RootURL, "url of 591 rent search")
+	return q
+}
+
+// parse resolve -region and -section names, fill -section with every section of the region when empty,
+// then validate the query
+func (q *queryFlags) parse() error {
+	region, err := scraper.FindRegion(q.region)
+	if err != nil {
+		return err
+	}
+
+	sections := region.Sections
+	if strings.TrimSpace(q.query.Section) != "" {
+		sections, err = region.SectionCodes(strings.Split(q.query.Section, ","))
+		if err != nil {
+			return err
+		}
+	}
+
+	q.query.Region = region.Code
+	q.query.Section = strings.Join(sections, ",")
+	q.regionName = region.Name
+	return q.query.Validate()
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	scraper "web_scraper"
+)
+
+// scraperFlags is how 591 is requested
+type scraperFlags struct {
+	rps         float64
+	burst       int
+	concurrency int
+	cacheDir    string
+	cacheTTL    time.Duration
+	cacheBytes  int64
+	refresh     bool
+}
+
+// newScraperFlags register rate limit and cache flags
+func newScraperFlags(fs *flag.FlagSet) *scraperFlags {
+	s := &scraperFlags{}
+	fs.Float64Var(&s.rps, "rps", 5, "requests per second, 0 for no limit")
+	fs.IntVar(&s.burst, "burst", 5, "requests sent at once")
+	fs.IntVar(&s.concurrency, "concurrency", 5, "pages requested at the same time")
+	fs.StringVar(&s.cacheDir, "cache", "", "directory caching list and detail pages, no cache when empty, ex: .591cache")
+	fs.DurationVar(&s.cacheTTL, "cache-ttl", 24*time.Hour, "how long a cached page is used")
+	fs.Int64Var(&s.cacheBytes, "cache-size", 512<<20, "max bytes of the cache directory")
+	fs.BoolVar(&s.refresh, "refresh", false, "fetch every page again and update the cache")
+	return s
+}
+
+func (s *scraperFlags) newFiveN1() (*scraper.FiveN1, error) {
+	options := []scraper.Option{scraper.WithRateLimit(s.rps, s.burst), scraper.WithConcurrency(s.concurrency)}
+	if s.cacheDir != "" {
+		cache, err := scraper.NewDiskCache(s.cacheDir, s.cacheTTL, s.cacheBytes)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, scraper.WithCache(cache))
+		if s.refresh {
+			options = append(options, scraper.WithCacheRefresh())
+		}
+	}
+
+	return scraper.NewFiveN1(options...), nil
+}
+
+func runScrape(fs *flag.FlagSet, args []string) error {
+	query := newQueryFlags(fs)
+	client := newScraperFlags(fs)
+	list := listFlags(fs)
+	out := outputFlags(fs, "{date}-{region}")
+	out.formatFlags(fs, "xlsx")
+	detail := fs.Bool("detail", true, "scrape the detail page of every rental for phone, layout and community")
+	dbFile := fs.String("db", "", "sqlite database keeping history of rentals, ex: rentals.db")
+	err := parseArgs(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	for _, parse := range []func() error{query.parse, list.parse, out.parse} {
+		if err := parse(); err != nil {
+			return err
+		}
+	}
+	filename, err := out.filename(map[string]string{"region": query.regionName})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cancelOnInterrupt(cancel)
+
+	s, err := client.newFiveN1()
+	if err != nil {
+		return err
+	}
+	// the same search is scraped again with the same url, so it is the scope of the history
+	scope, err := query.query.URL()
+	if err != nil {
+		return err
+	}
+	return search{query: query.query, scope: scope, detail: *detail, list: list, out: out, filename: filename}.run(ctx, s, *dbFile, time.Now())
+}
+
+// search is a Query with how its rentals are cleaned up and saved
+type search struct {
+	query    *scraper.Query
+	scope    string // scope of the history, which is delisted only by searches of the same scope
+	detail   bool
+	list     *rentalFlags
+	out      *output
+	filename string
+}
+
+// run scrape, clean up and save rentals of the search, saving history seen at seenAt into dbFile unless empty
+func (se search) run(ctx context.Context, s *scraper.FiveN1, dbFile string, seenAt time.Time) error {
+	startTime := time.Now()
+
+	rentals, err := s.ScrapeRentalsContext(ctx, se.query)
+	if err != nil {
+		log.Printf("scrape rentals error: %v", err)
+	}
+	failed := unexpectedPage(err)
+	listed := err == nil
+	if len(rentals) == 0 {
+		return fmt.Errorf("no rental scraped")
+	}
+
+	if se.detail {
+		err = s.ScrapeRentalsDetailContext(ctx, rentals)
+		if err != nil {
+			log.Printf("scrape rentals detail error: %v", err)
+		}
+		failed = failed || unexpectedPage(err)
+	}
+
+	rentals.ReplaceSection()
+	rentals = se.list.dedupe(rentals)
+	if dbFile != "" {
+		// a partial run would delist every rental which wasn't fetched, so it is only saved as files
+		if listed && !failed && ctx.Err() == nil {
+			saveHistory(dbFile, se.scope, rentals, seenAt)
+		} else {
+			log.Printf("history is not saved since rentals may be incomplete")
+		}
+	}
+	rentals = se.list.apply(rentals)
+	rentals.Print()
+	err = se.out.save(rentals, se.filename)
+
+	log.Printf("execution time %s", time.Since(startTime))
+
+	if err != nil {
+		return err
+	}
+	if failed {
+		return errIncomplete
+	}
+	return nil
+}
+
+func runDetail(fs *flag.FlagSet, args []string) error {
+	client := newScraperFlags(fs)
+	out := outputFlags(fs, "{input}-detail")
+	out.formatFlags(fs, "json")
+	err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	err = out.parse()
+	if err != nil {
+		return err
+	}
+	filename, err := out.filename(map[string]string{"input": inputName(fs.Arg(0))})
+	if err != nil {
+		return err
+	}
+
+	rentals, err := scraper.LoadRentals(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cancelOnInterrupt(cancel)
+
+	s, err := client.newFiveN1()
+	if err != nil {
+		return err
+	}
+	err = s.ScrapeRentalsDetailContext(ctx, rentals)
+	if err != nil {
+		log.Printf("scrape rentals detail error: %v", err)
+	}
+	failed := unexpectedPage(err)
+	err = out.save(rentals, filename)
+	if err != nil {
+		return err
+	}
+	if failed {
+		return errIncomplete
+	}
+	return nil
+}
+
+func runExport(fs *flag.FlagSet, args []string) error {
+	list := listFlags(fs)
+	out := outputFlags(fs, "{input}")
+	out.formatFlags(fs, "xlsx")
+	dedupe := fs.Bool("dedupe", false, "remove rentals listed twice, ex: when exporting several runs together")
+	err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	for _, parse := range []func() error{list.parse, out.parse} {
+		if err := parse(); err != nil {
+			return err
+		}
+	}
+	filename, err := out.filename(map[string]string{"input": inputName(fs.Arg(0))})
+	if err != nil {
+		return err
+	}
+
+	var rentals scraper.Rentals
+	for _, input := range fs.Args() {
+		loaded, err := scraper.LoadRentals(input)
+		if err != nil {
+			return err
+		}
+		rentals = append(rentals, loaded...)
+	}
+
+	if *dedupe {
+		rentals = list.dedupe(rentals)
+	}
+	rentals = list.apply(rentals)
+	return out.save(rentals, filename)
+}
+
+// saveHistory save rentals into the database, rentals are still saved as files when it fails
+func saveHistory(dbFile, scope string, rentals scraper.Rentals, seenAt time.Time) {
+	store, err := scraper.OpenStore(dbFile)
+	if err != nil {
+		log.Printf("open history error: %v", err)
+		return
+	}
+	defer store.Close()
+
+	err = store.Save(scope, rentals, seenAt)
+	if err != nil {
+		log.Printf("save history error: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Codes of Query fields, they have no String method so go-querystring still encode the codes.
+
+// Kind is 租屋類型 of Query.Kind
+type Kind int
+
+const (
+	KindAny          Kind = 0  // 不限
+	KindWholeFloor   Kind = 1  // 整層住家
+	KindStudio       Kind = 2  // 獨立套房
+	KindSharedStudio Kind = 3  // 分租套房
+	KindRoom         Kind = 4  // 雅房
+	KindParking      Kind = 8  // 車位
+	KindOther        Kind = 24 // 其他
+)
+
+// RentPrice is 租金 of Query.RentPrice, a preset or a range by RentPriceRange
+type RentPrice string
+
+const (
+	RentPrice5kTo10k  RentPrice = "2"
+	RentPrice10kTo20k RentPrice = "3"
+	RentPrice20kTo30k RentPrice = "4"
+)
+
+// Sex is 性別 of Query.Sex
+type Sex int
+
+const (
+	SexAny    Sex = 0 // 不限
+	SexMale   Sex = 1 // 男性
+	SexFemale Sex = 2 // 女性
+)
+
+// Shape is 房屋類型 of Query.Shape
+type Shape string
+
+const (
+	ShapeApartment Shape = "1" // 公寓
+	ShapeElevator  Shape = "2" // 電梯大樓
+	ShapeTownhouse Shape = "3" // 透天厝
+	ShapeVilla     Shape = "4" // 別墅
+)
+
+// Pattern is 格局 of Query.Pattern, join them by Patterns for Query.PatternMore
+type Pattern string
+
+const (
+	PatternAny             Pattern = "0" // 不限
+	PatternOneRoom         Pattern = "1" // 一房
+	PatternTwoRooms        Pattern = "2" // 兩房
+	PatternThreeRooms      Pattern = "3" // 三房
+	PatternFourRooms       Pattern = "4" // 四房
+	PatternFiveRoomsOrMore Pattern = "5" // 五房以上
+)
+
+// Floor is 樓層 of Query.Floor, a preset or a range by FloorRange
+type Floor string
+
+const (
+	FloorAny     Floor = "0,0"  // 不限
+	FloorFirst   Floor = "0,1"  // 一樓
+	Floor2To6    Floor = "2,6"  // 二樓到六樓
+	Floor6To12   Floor = "6,12" // 六樓到十二樓
+	FloorAbove12 Floor = "12,"  // 十二樓以上
+)
+
+// Facility is 提供設備, join them by Options for Query.Option
+type Facility string
+
+const (
+	OptionTV          Facility = "tv"         // 電視
+	OptionAirCon      Facility = "cold"       // 冷氣
+	OptionFridge      Facility = "icebox"     // 冰箱
+	OptionWaterHeater Facility = "hotwater"   // 熱水器
+	OptionNaturalGas  Facility = "naturalgas" // 天然瓦斯
+	OptionCableTV     Facility = "four"       // 第四台
+	OptionInternet    Facility = "broadband"  // 網路
+	OptionWasher      Facility = "washer"     // 洗衣機
+	OptionBed         Facility = "bed"        // 床
+	OptionWardrobe    Facility = "wardrobe"   // 衣櫃
+	OptionSofa        Facility = "sofa"       // 沙發
+)
+
+// Condition is 其他條件, join them by Others for Query.Other
+type Condition string
+
+const (
+	OtherParking    Condition = "cartplace" // 有車位
+	OtherElevator   Condition = "lift"      // 有電梯
+	OtherBalcony    Condition = "balcony_1" // 有陽台
+	OtherCooking    Condition = "cook"      // 可開伙
+	OtherPet        Condition = "pet"       // 可養寵物
+	OtherNearMRT    Condition = "tragoods"  // 近捷運
+	OtherShortLease Condition = "lease"     // 可短期租賃
+)
+
+// RentPriceRange is rent from min to max, 0 max means no upper bound
+func RentPriceRange(min, max int) RentPrice {
+	return RentPrice(formatRange(min, max))
+}
+
+// FloorRange is floors from min to max, 0 max means no upper bound
+func FloorRange(min, max int) Floor {
+	return Floor(formatRange(min, max))
+}
+
+func formatRange(min, max int) string {
+	if max == 0 {
+		return fmt.Sprintf("%d,", min)
+	}
+
+	return fmt.Sprintf("%d,%d", min, max)
+}
+
+// Options join facilities for Query.Option, ex: Options(OptionAirCon, OptionWasher)
+func Options(facilities ...Facility) string {
+	codes := make([]string, len(facilities))
+	for i, f := range facilities {
+		codes[i] = string(f)
+	}
+
+	return strings.Join(codes, ",")
+}
+
+// Others join conditions for Query.Other, ex: Others(OtherPet, OtherCooking)
+func Others(conditions ...Condition) string {
+	codes := make([]string, len(conditions))
+	for i, c := range conditions {
+		codes[i] = string(c)
+	}
+
+	return strings.Join(codes, ",")
+}
+
+// Patterns join patterns for Query.PatternMore, ex: Patterns(PatternOneRoom, PatternTwoRooms)
+func Patterns(patterns ...Pattern) string {
+	codes := make([]string, len(patterns))
+	for i, p := range patterns {
+		codes[i] = string(p)
+	}
+
+	return strings.Join(codes, ",")
+}
+
+// code is a valid value of a Query field with its name shown in errors
+type code struct {
+	value string
+	name  string
+}
+
+var (
+	kindCodes      = []code{{"0", "不限"}, {"1", "整層住家"}, {"2", "獨立套房"}, {"3", "分租套房"}, {"4", "雅房"}, {"8", "車位"}, {"24", "其他"}}
+	rentPriceCodes = []code{{"2", "5k-10k"}, {"3", "10k-20k"}, {"4", "20k-30k"}}
+	sexCodes       = []code{{"0", "不限"}, {"1", "男性"}, {"2", "女性"}}
+	shapeCodes     = []code{{"1", "公寓"}, {"2", "電梯大樓"}, {"3", "透天厝"}, {"4", "別墅"}}
+	patternCodes   = []code{{"0", "不限"}, {"1", "一房"}, {"2", "兩房"}, {"3", "三房"}, {"4", "四房"}, {"5", "五房以上"}}
+	orderCodes     = []code{{"posttime", "刊登時間"}, {"money", "租金"}}
+	orderTypeCodes = []code{{"desc", "遞減"}, {"asc", "遞增"}}
+	flagCodes      = []code{{"1", "是"}}
+	facilityCodes  = []code{{"tv", "電視"}, {"cold", "冷氣"}, {"icebox", "冰箱"}, {"hotwater", "熱水器"}, {"naturalgas", "天然瓦斯"},
+		{"four", "第四台"}, {"broadband", "網路"}, {"washer", "洗衣機"}, {"bed", "床"}, {"wardrobe", "衣櫃"}, {"sofa", "沙發"}}
+	conditionCodes = []code{{"cartplace", "有車位"}, {"lift", "有電梯"}, {"balcony_1", "有陽台"}, {"cook", "可開伙"},
+		{"pet", "可養寵物"}, {"tragoods", "近捷運"}, {"lease", "可短期租賃"}}
+)
+
+func hasCode(codes []code, value string) bool {
+	for _, c := range codes {
+		if c.value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validCodes list codes with their names for errors, ex: "1 公寓, 2 電梯大樓"
+func validCodes(codes []code) string {
+	valid := make([]string, len(codes))
+	for i, c := range codes {
+		valid[i] = c.value + " " + c.name
+	}
+
+	return strings.Join(valid, ", ")
+}
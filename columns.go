@@ -0,0 +1,195 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Column is a field of Rental written by SaveAsXLSX, SaveAsCSV and SaveAsJSONL
+type Column struct {
+	Name   string                        // json name, ex: "price"
+	Header string                        // ex: "租金"
+	Value  func(r Rental) interface{}    // written value, parsed numbers are written as numbers
+	Set    func(r *Rental, value string) // set field from the written value, nil for columns derived by parseNumbers
+	Format string                        // xlsx number format of numbers, ex: "#,##0"
+	Link   bool                          // written as a hyperlink in xlsx
+}
+
+// columns are every Column in the default order, extra columns come last
+var columns = []Column{
+	{Name: "section", Header: "區", Value: func(r Rental) interface{} { return r.Section }, Set: func(r *Rental, v string) { r.Section = v }},
+	{Name: "title", Header: "標題", Value: func(r Rental) interface{} { return r.Title }, Set: func(r *Rental, v string) { r.Title = v }},
+	{Name: "optionType", Header: "類型", Value: func(r Rental) interface{} { return r.OptionType }, Set: func(r *Rental, v string) { r.OptionType = v }},
+	{Name: "price", Header: "租金", Format: "#,##0", Value: func(r Rental) interface{} { return r.numberOrText("price", r.PriceValue, r.Price) }, Set: func(r *Rental, v string) { r.Price = v }},
+	{Name: "layout", Header: "格局", Value: func(r Rental) interface{} { return r.Layout }, Set: func(r *Rental, v string) { r.Layout = v }},
+	{Name: "ping", Header: "坪數", Format: "0.0#", Value: func(r Rental) interface{} { return r.numberOrText("ping", r.PingValue, r.Ping) }, Set: func(r *Rental, v string) { r.Ping = v }},
+	{Name: "floor", Header: "樓層", Value: func(r Rental) interface{} { return r.Floor }, Set: func(r *Rental, v string) { r.Floor = v }},
+	{Name: "floorValue", Header: "樓", Value: func(r Rental) interface{} { return r.floorNumber(r.FloorValue) }},
+	{Name: "totalFloors", Header: "總樓層", Value: func(r Rental) interface{} { return r.floorNumber(r.TotalFloors) }},
+	{Name: "community", Header: "社區", Value: func(r Rental) interface{} { return r.Community }, Set: func(r *Rental, v string) { r.Community = v }},
+	{Name: "postBy", Header: "聯絡人", Value: func(r Rental) interface{} { return r.PostBy }, Set: func(r *Rental, v string) { r.PostBy = v }},
+	{Name: "phone", Header: "電話", Value: func(r Rental) interface{} { return r.Phone }, Set: func(r *Rental, v string) { r.Phone = v }},
+	{Name: "url", Header: "連結", Link: true, Value: func(r Rental) interface{} { return r.URL }, Set: func(r *Rental, v string) { r.URL = v }},
+	{Name: "id", Header: "編號", Value: func(r Rental) interface{} { return r.ID }, Set: func(r *Rental, v string) { r.ID = v }},
+	{Name: "address", Header: "地址", Value: func(r Rental) interface{} { return r.Address }, Set: func(r *Rental, v string) { r.Address = v }},
+	{Name: "region", Header: "縣市", Value: func(r Rental) interface{} { return r.regionName() }, Set: func(r *Rental, v string) { r.setRegion(v) }},
+}
+
+// 區	標題	類型	租金	格局	坪數	樓層	樓	總樓層	社區	聯絡人	電話	連結
+const defaultColumns = 13
+
+// AllColumns return every Column, the default ones first
+func AllColumns() []Column {
+	return append([]Column{}, columns...)
+}
+
+// DefaultColumns return columns written when none is selected:
+// 區, 標題, 類型, 租金, 格局, 坪數, 樓層, 樓, 總樓層, 社區, 聯絡人, 電話 and 連結
+func DefaultColumns() []Column {
+	return append([]Column{}, columns[:defaultColumns]...)
+}
+
+// SelectColumns return columns by Name or Header in the given order, ex: "title", "price", "電話"
+func SelectColumns(names ...string) ([]Column, error) {
+	selected := []Column{}
+	for _, name := range names {
+		c, ok := columnByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q, valid columns: %s", name, strings.Join(columnNames(columns), ", "))
+		}
+		selected = append(selected, c)
+	}
+
+	return selected, nil
+}
+
+// ParseColumns parse columns separated by ",", an empty spec select nothing so writers write their default columns
+func ParseColumns(spec string) ([]Column, error) {
+	names := splitList(spec)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	return SelectColumns(names...)
+}
+
+func columnByName(name string) (Column, bool) {
+	for _, c := range columns {
+		if c.Name == name || c.Header == name {
+			return c, true
+		}
+	}
+
+	return Column{}, false
+}
+
+func columnNames(columns []Column) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+
+	return names
+}
+
+// orDefault return DefaultColumns when no column is selected
+func orDefault(columns []Column) []Column {
+	if len(columns) == 0 {
+		return DefaultColumns()
+	}
+
+	return columns
+}
+
+// floorNumber return nothing for a missing floor, there is no floor 0, ex: the floor of 整棟/4
+func (r Rental) floorNumber(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+
+	return n
+}
+
+func columnHeaders(columns []Column) []interface{} {
+	headers := make([]interface{}, len(columns))
+	for i, c := range columns {
+		headers[i] = c.Header
+	}
+
+	return headers
+}
+
+func columnValues(columns []Column, r Rental) []interface{} {
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		values[i] = c.Value(r)
+	}
+
+	return values
+}
+
+// xlsxValues is columnValues with links as xlsxLink
+func xlsxValues(columns []Column, r Rental) []interface{} {
+	values := columnValues(columns, r)
+	for i, c := range columns {
+		if s, ok := values[i].(string); ok && c.Link {
+			values[i] = xlsxLink(s)
+		}
+	}
+
+	return values
+}
+
+// setNumberFormats set Format of columns starting after offset columns
+func setNumberFormats(x *xlsx, columns []Column, offset int) {
+	for i, c := range columns {
+		if c.Format != "" {
+			x.SetNumberFormat(offset+i+1, c.Format)
+		}
+	}
+}
+
+// columnsByHeader map a header row back to columns, unknown headers are nil
+func columnsByHeader(headers []string) []*Column {
+	found := make([]*Column, len(headers))
+	for i, header := range headers {
+		for j := range columns {
+			if columns[j].Header == header {
+				found[i] = &columns[j]
+			}
+		}
+	}
+
+	return found
+}
+
+var detailIDPattern = regexp.MustCompile(`rent-detail-(\d+)\.html`)
+
+// idFromURL return ID of a detail url, ex: https://rent.591.com.tw/rent-detail-9538360.html is R9538360
+func idFromURL(url string) string {
+	match := detailIDPattern.FindStringSubmatch(url)
+	if match == nil {
+		return ""
+	}
+
+	return "R" + match[1]
+}
+
+// regionName return the name of Region, "" when unknown
+func (r Rental) regionName() string {
+	region, err := FindRegion(strconv.Itoa(r.Region))
+	if r.Region == 0 || err != nil {
+		return ""
+	}
+
+	return region.Name
+}
+
+// setRegion set Region by a code or name, unknown regions are left 0
+func (r *Rental) setRegion(v string) {
+	if region, err := FindRegion(v); err == nil && v != "" {
+		r.Region = region.Code
+	}
+}
@@ -0,0 +1,113 @@
+package scraper
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DedupeKey return the key of a rental, rentals with the same key are duplicates.
+// An empty key means the rental can't be compared and is never a duplicate.
+type DedupeKey func(r Rental) string
+
+// SameID is the same rental scraped more than once, ex: from overlapping sections
+func SameID(r Rental) string {
+	return r.ID
+}
+
+// SameUnit is the same unit reposted under a new ID, it compares normalized address,
+// price, ping, floor and phone when it is known after ScrapeRentalsDetail.
+// Rentals without address or whose numbers failed to parse never match.
+func SameUnit(r Rental) string {
+	address := normalizeAddress(r.Address)
+	if address == "" || len(r.ParseErrors) > 0 {
+		return ""
+	}
+
+	return strings.Join([]string{
+		address,
+		strconv.Itoa(r.PriceValue),
+		strconv.FormatFloat(r.PingValue, 'f', -1, 64),
+		strconv.Itoa(r.FloorValue) + "/" + strconv.Itoa(r.TotalFloors),
+		normalizePhone(r.Phone),
+	}, "|")
+}
+
+// Merge is a kept rental with its duplicates removed by Dedupe
+type Merge struct {
+	Kept   Rental
+	Merged Rentals
+}
+
+// Dedupe remove duplicates of any key, the first rental is kept and later ones are merged into it.
+// It returns deduped rentals in their order and which rentals were merged.
+func (r Rentals) Dedupe(keys ...DedupeKey) (Rentals, []Merge) {
+	deduped := Rentals{}
+	merged := map[int]Rentals{} // index of deduped to its duplicates
+	seen := make([]map[string]int, len(keys))
+	for i := range seen {
+		seen[i] = map[string]int{}
+	}
+
+	for _, rental := range r {
+		kept := -1
+		for i, key := range keys {
+			k := key(rental)
+			if k == "" {
+				continue
+			}
+			if index, ok := seen[i][k]; ok {
+				kept = index
+				break
+			}
+		}
+
+		if kept >= 0 {
+			merged[kept] = append(merged[kept], rental)
+			continue
+		}
+
+		deduped = append(deduped, rental)
+		for i, key := range keys {
+			if k := key(rental); k != "" {
+				seen[i][k] = len(deduped) - 1
+			}
+		}
+	}
+
+	var merges []Merge
+	for i, rental := range deduped {
+		if duplicates, ok := merged[i]; ok {
+			merges = append(merges, Merge{Kept: rental, Merged: duplicates})
+		}
+	}
+
+	return deduped, merges
+}
+
+// normalizeAddress ignore spaces, punctuation, letter case, full-width characters and 臺/台
+func normalizeAddress(address string) string {
+	var b strings.Builder
+	for _, r := range strings.Replace(address, "臺", "台", -1) {
+		// full-width ASCII, ex: "１２號" is "12號"
+		if r >= '！' && r <= '～' {
+			r -= '！' - '!'
+		}
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// normalizePhone keep digits only, ex: "0912-345-678" is "0912345678"
+func normalizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRentals_Dedupe(t *testing.T) {
+	rentals := parsedRentals(Rentals{
+		{ID: "1", Address: "中正區羅斯福路一段 12號", Price: "12,000 元/月", Ping: "8坪", Floor: "樓層：3/5"},
+		{ID: "2", Address: "大安區復興南路", Price: "32,000 元/月", Ping: "28坪", Floor: "樓層：B1/12"},
+		{ID: "1", Address: "中正區羅斯福路一段 12號", Price: "12,000 元/月", Ping: "8坪", Floor: "樓層：3/5"},
+		{ID: "3", Address: "中正區羅斯福路一段１２號", Price: "12,000 元 / 月", Ping: "8.0坪", Floor: "樓層：3/5"},
+		{ID: "4", Address: "中正區羅斯福路一段12號", Price: "12,000 元/月", Ping: "8坪", Floor: "樓層：4/5"},
+		{ID: "5", Address: "", Price: "面議", Ping: "8坪", Floor: "樓層：4/5"},
+		{ID: "6", Address: "", Price: "面議", Ping: "8坪", Floor: "樓層：4/5"},
+	})
+
+	deduped, merges := rentals.Dedupe(SameID)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6"}, rentalIDs(deduped))
+	assert.Len(t, merges, 1)
+	assert.Equal(t, "1", merges[0].Kept.ID)
+	assert.Equal(t, []string{"1"}, rentalIDs(merges[0].Merged))
+
+	deduped, merges = rentals.Dedupe(SameID, SameUnit)
+	assert.Equal(t, []string{"1", "2", "4", "5", "6"}, rentalIDs(deduped))
+	assert.Len(t, merges, 1)
+	assert.Equal(t, "1", merges[0].Kept.ID)
+	assert.Equal(t, []string{"1", "3"}, rentalIDs(merges[0].Merged))
+
+	deduped, merges = rentals.Dedupe()
+	assert.Equal(t, rentalIDs(rentals), rentalIDs(deduped))
+	assert.Len(t, merges, 0)
+}
+
+func TestSameUnit(t *testing.T) {
+	a := Rental{Address: "台北市中正區 羅斯福路1段-12號", Price: "12,000 元/月", Ping: "8坪", Floor: "樓層：3/5", Phone: "0912-345-678"}
+	b := Rental{Address: "臺北市中正區羅斯福路1段12號", Price: "12000元/月", Ping: "8坪", Floor: "樓層：3F/5F", Phone: "0912 345 678"}
+	a.parseNumbers()
+	b.parseNumbers()
+	assert.NotEqual(t, "", SameUnit(a))
+	assert.Equal(t, SameUnit(a), SameUnit(b))
+
+	b.Phone = "0922-000-000"
+	assert.NotEqual(t, SameUnit(a), SameUnit(b), "different phone")
+
+	c := Rental{Address: "中正區", Price: "面議", Ping: "8坪", Floor: "樓層：3/5"}
+	c.parseNumbers()
+	assert.Equal(t, "", SameUnit(c), "price failed to parse")
+}
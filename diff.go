@@ -0,0 +1,162 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Change is a rental listed in both runs whose price or title changed
+type Change struct {
+	Old    Rental   `json:"old"`
+	New    Rental   `json:"new"`
+	Fields []string `json:"fields"` // changed fields, "price" and/or "title"
+}
+
+// RentalsDiff is the difference between two scrape runs keyed by Rental.ID
+type RentalsDiff struct {
+	Added   Rentals  `json:"added"`   // newly listed, in new order
+	Removed Rentals  `json:"removed"` // delisted, in old order
+	Changed []Change `json:"changed"` // in new order
+}
+
+// Diff compare rentals of an old run with a new run by Rental.ID,
+// rentals without ID are ignored.
+func Diff(old, new Rentals) RentalsDiff {
+	diff := RentalsDiff{
+		Added:   Rentals{},
+		Removed: Rentals{},
+		Changed: []Change{},
+	}
+
+	olds := map[string]Rental{}
+	for _, rental := range old {
+		if rental.ID != "" {
+			olds[rental.ID] = rental
+		}
+	}
+	news := map[string]bool{}
+
+	for _, rental := range new {
+		if rental.ID == "" || news[rental.ID] {
+			continue
+		}
+		news[rental.ID] = true
+
+		o, ok := olds[rental.ID]
+		if !ok {
+			diff.Added = append(diff.Added, rental)
+			continue
+		}
+		if fields := changedFields(o, rental); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, Change{Old: o, New: rental, Fields: fields})
+		}
+	}
+
+	for _, rental := range old {
+		if rental.ID != "" && !news[rental.ID] {
+			news[rental.ID] = true // report a duplicated old rental once
+			diff.Removed = append(diff.Removed, rental)
+		}
+	}
+
+	return diff
+}
+
+// changedFields compare parsed price when both parsed, the text otherwise
+func changedFields(old, new Rental) []string {
+	var fields []string
+
+	_, oldFailed := old.ParseErrors["price"]
+	_, newFailed := new.ParseErrors["price"]
+	if oldFailed || newFailed {
+		if strings.TrimSpace(old.Price) != strings.TrimSpace(new.Price) {
+			fields = append(fields, "price")
+		}
+	} else if old.PriceValue != new.PriceValue {
+		fields = append(fields, "price")
+	}
+
+	if strings.TrimSpace(old.Title) != strings.TrimSpace(new.Title) {
+		fields = append(fields, "title")
+	}
+
+	return fields
+}
+
+// Empty report whether nothing changed
+func (d RentalsDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d RentalsDiff) SaveAsJSON(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("create %s error %v", filename, err)
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(d)
+	if err != nil {
+		return fmt.Errorf("json encode error %v", err)
+	}
+
+	return file.Close()
+}
+
+// SaveAsXLSX save 新上架, 已下架 and 變更 sheets,
+// 變更 has the changed fields, old price and old title before columns of the new rental.
+// Only columns are written when given like Rentals.SaveAsXLSX.
+func (d RentalsDiff) SaveAsXLSX(filename string, columns ...Column) error {
+	x := newXlsx()
+	columns = orDefault(columns)
+
+	err := x.NewSheet("新上架")
+	if err != nil {
+		return err
+	}
+	err = d.Added.writeXLSX(x, columns)
+	if err != nil {
+		return err
+	}
+
+	err = x.NewSheet("已下架")
+	if err != nil {
+		return err
+	}
+	err = d.Removed.writeXLSX(x, columns)
+	if err != nil {
+		return err
+	}
+
+	err = x.NewSheet("變更")
+	if err != nil {
+		return err
+	}
+	err = x.WriteHeader(append([]interface{}{"變更", "原租金", "原標題"}, columnHeaders(columns)...)...)
+	if err != nil {
+		return fmt.Errorf("xlsx.WriteHeader error %v", err)
+	}
+	x.SetNumberFormat(2, "#,##0")
+	setNumberFormats(x, columns, 3)
+	for _, change := range d.Changed {
+		old := change.Old
+		row := []interface{}{
+			strings.Join(change.Fields, ","),
+			old.numberOrText("price", old.PriceValue, old.Price),
+			old.Title,
+		}
+		err := x.WriteNextRow(append(row, xlsxValues(columns, change.New)...)...)
+		if err != nil {
+			return fmt.Errorf("xlsx.WriteNextRow error %v", err)
+		}
+	}
+
+	err = x.Save(filename)
+	if err != nil {
+		return fmt.Errorf("xlsx save file error %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func testDiffRentals() (Rentals, Rentals) {
+	old := parsedRentals(Rentals{
+		{ID: "1", Title: "套房", Price: "12,000 元/月"},
+		{ID: "2", Title: "雅房", Price: "8,000 元/月"},
+		{ID: "3", Title: "整層", Price: "面議"},
+		{ID: "4", Title: "下架", Price: "9,000 元/月"},
+	})
+	new := parsedRentals(Rentals{
+		{ID: "5", Title: "新上架", Price: "15,000 元/月"},
+		{ID: "3", Title: "整層", Price: "30,000 元/月"},
+		{ID: "2", Title: "近捷運雅房", Price: "8,000元/月"},
+		{ID: "1", Title: "套房", Price: "12,000 元 / 月"},
+	})
+
+	return old, new
+}
+
+func TestDiff(t *testing.T) {
+	old, new := testDiffRentals()
+
+	diff := Diff(old, new)
+	assert.Equal(t, []string{"5"}, rentalIDs(diff.Added))
+	assert.Equal(t, []string{"4"}, rentalIDs(diff.Removed))
+	assert.Len(t, diff.Changed, 2)
+	assert.Equal(t, "3", diff.Changed[0].New.ID)
+	assert.Equal(t, []string{"price"}, diff.Changed[0].Fields)
+	assert.Equal(t, "面議", diff.Changed[0].Old.Price)
+	assert.Equal(t, "2", diff.Changed[1].New.ID)
+	assert.Equal(t, []string{"title"}, diff.Changed[1].Fields)
+	assert.False(t, diff.Empty())
+
+	assert.True(t, Diff(new, new).Empty())
+}
+
+func TestRentalsDiff_Save(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	old, new := testDiffRentals()
+	diff := Diff(old, new)
+
+	filename := filepath.Join(dir, "diff.json")
+	assert.Nil(t, diff.SaveAsJSON(filename))
+	data, err := ioutil.ReadFile(filename)
+	assert.Nil(t, err)
+	var loaded RentalsDiff
+	assert.Nil(t, json.Unmarshal(data, &loaded))
+	assert.Equal(t, rentalIDs(diff.Added), rentalIDs(loaded.Added))
+	assert.Equal(t, []string{"price"}, loaded.Changed[0].Fields)
+
+	filename = filepath.Join(dir, "diff.xlsx")
+	assert.Nil(t, diff.SaveAsXLSX(filename))
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"新上架", "已下架", "變更"}, f.GetSheetList())
+
+	rows, err := f.GetRows("已下架")
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "下架", rows[1][1])
+
+	rows, err = f.GetRows("變更")
+	assert.Nil(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, []string{"變更", "原租金", "原標題", "區"}, rows[0][:4])
+	assert.Equal(t, []string{"price", "面議", "整層"}, rows[1][:3])
+	assert.Equal(t, []string{"title", "8000", "雅房"}, rows[2][:3])
+}
@@ -0,0 +1,181 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheFileExt is the extension of files written by DiskCache, other files in Dir are never touched
+const cacheFileExt = ".591cache"
+
+// DiskCache is a Cache storing responses under Dir, one file per key.
+// Errors are ignored since a cache miss only cost another request.
+type DiskCache struct {
+	Dir      string
+	TTL      time.Duration // responses older than TTL are fetched again, 0 means never expire
+	MaxBytes int64         // oldest responses are removed once Dir exceed MaxBytes, 0 means no limit
+
+	mu    sync.Mutex
+	size  int64 // bytes of files in Dir, kept up to date by Set so Dir is only listed to evict
+	sized bool  // size is known, Dir is listed once by the first Set
+}
+
+// NewDiskCache create dir if it doesn't exist
+func NewDiskCache(dir string, ttl time.Duration, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create cache dir %s error %v", dir, err)
+	}
+
+	return &DiskCache{
+		Dir:      dir,
+		TTL:      ttl,
+		MaxBytes: maxBytes,
+	}, nil
+}
+
+func (d *DiskCache) Get(key string) (*Response, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	filename := d.filename(key)
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, false
+	}
+
+	if d.TTL > 0 && time.Since(info.ModTime()) > d.TTL {
+		if os.Remove(filename) == nil {
+			d.size -= info.Size()
+		}
+		return nil, false
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, false
+	}
+
+	res := &Response{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, false
+	}
+
+	return res, true
+}
+
+func (d *DiskCache) Set(key string, res *Response) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+
+	if !d.sized {
+		d.size = totalSize(d.files())
+		d.sized = true
+	}
+	filename := d.filename(key)
+	old, statErr := os.Stat(filename)
+
+	// write to a temp file first so a crash never leave half a response in the cache
+	tmp, err := ioutil.TempFile(d.Dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	closeErr := tmp.Close()
+	if err != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+
+	d.size += int64(len(data))
+	if statErr == nil {
+		d.size -= old.Size()
+	}
+	if d.MaxBytes > 0 && d.size > d.MaxBytes {
+		d.evict()
+	}
+}
+
+// Clear remove every cached response
+func (d *DiskCache) Clear() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sized = false
+	for _, info := range d.files() {
+		if err := os.Remove(filepath.Join(d.Dir, info.Name())); err != nil {
+			return fmt.Errorf("remove cache file error %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (d *DiskCache) filename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(d.Dir, hex.EncodeToString(sum[:])+cacheFileExt)
+}
+
+// evict remove the oldest responses until Dir is under 90% of MaxBytes,
+// the room left let the next Sets skip listing Dir again
+func (d *DiskCache) evict() {
+	files := d.files()
+	d.size = totalSize(files)
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+
+	for _, info := range files {
+		if d.size <= d.MaxBytes/10*9 {
+			return
+		}
+		if err := os.Remove(filepath.Join(d.Dir, info.Name())); err == nil {
+			d.size -= info.Size()
+		}
+	}
+}
+
+func totalSize(files []os.FileInfo) int64 {
+	var size int64
+	for _, info := range files {
+		size += info.Size()
+	}
+
+	return size
+}
+
+// files return every file written by DiskCache
+func (d *DiskCache) files() []os.FileInfo {
+	infos, err := ioutil.ReadDir(d.Dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []os.FileInfo
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), cacheFileExt) {
+			files = append(files, info)
+		}
+	}
+
+	return files
+}
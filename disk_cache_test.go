@@ -0,0 +1,123 @@
+package scraper
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTempCacheDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "591cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestDiskCache(t *testing.T) {
+	t.Run("get what was set", func(t *testing.T) {
+		dir := newTempCacheDir(t)
+		defer os.RemoveAll(dir)
+
+		cache, err := NewDiskCache(filepath.Join(dir, "nested"), time.Hour, 0)
+		assert.Nil(t, err)
+
+		want := &Response{URL: "https://rent.591.com.tw/", StatusCode: 200, Header: http.Header{"A": {"b"}}, Body: []byte("<html>")}
+		cache.Set("key", want)
+
+		got, ok := cache.Get("key")
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+
+		_, ok = cache.Get("other key")
+		assert.False(t, ok)
+	})
+
+	t.Run("expire after TTL", func(t *testing.T) {
+		dir := newTempCacheDir(t)
+		defer os.RemoveAll(dir)
+
+		cache, _ := NewDiskCache(dir, time.Hour, 0)
+		cache.Set("key", &Response{StatusCode: 200})
+
+		past := time.Now().Add(-2 * time.Hour)
+		assert.Nil(t, os.Chtimes(cache.filename("key"), past, past))
+
+		_, ok := cache.Get("key")
+		assert.False(t, ok)
+		assert.Len(t, cache.files(), 0)
+	})
+
+	t.Run("remove oldest responses over MaxBytes", func(t *testing.T) {
+		dir := newTempCacheDir(t)
+		defer os.RemoveAll(dir)
+
+		body := []byte(strings.Repeat("a", 1000))
+		cache, _ := NewDiskCache(dir, 0, 3000)
+		for i, key := range []string{"1", "2", "3", "4"} {
+			cache.Set(key, &Response{StatusCode: 200, Body: body})
+			modTime := time.Now().Add(time.Duration(i-10) * time.Minute)
+			_ = os.Chtimes(cache.filename(key), modTime, modTime)
+		}
+		cache.Set("5", &Response{StatusCode: 200, Body: body})
+
+		_, ok := cache.Get("1")
+		assert.False(t, ok, "oldest is removed")
+		_, ok = cache.Get("5")
+		assert.True(t, ok)
+		assert.True(t, len(cache.files()) < 5)
+	})
+
+	t.Run("track size without listing Dir on every Set", func(t *testing.T) {
+		dir := newTempCacheDir(t)
+		defer os.RemoveAll(dir)
+
+		cache, _ := NewDiskCache(dir, time.Hour, 1<<20)
+		cache.Set("1", &Response{StatusCode: 200, Body: []byte("a")})
+		cache.Set("2", &Response{StatusCode: 200, Body: []byte("bb")})
+		cache.Set("1", &Response{StatusCode: 200, Body: []byte("ccc")})
+		assert.Equal(t, totalSize(cache.files()), cache.size, "an overwritten response is counted once")
+
+		past := time.Now().Add(-2 * time.Hour)
+		_ = os.Chtimes(cache.filename("2"), past, past)
+		_, ok := cache.Get("2")
+		assert.False(t, ok)
+		assert.Equal(t, totalSize(cache.files()), cache.size, "an expired response is not counted")
+	})
+
+	t.Run("serve FiveN1 from cache", func(t *testing.T) {
+		dir := newTempCacheDir(t)
+		defer os.RemoveAll(dir)
+
+		requested := 0
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			html, _ := ioutil.ReadFile("test_fixture/591_detail.html")
+			_, _ = w.Write(html)
+		}))
+		defer svr.Close()
+
+		cache, _ := NewDiskCache(dir, time.Hour, 0)
+
+		for i := 0; i < 2; i++ {
+			rental := &Rental{URL: svr.URL + "/rent-detail-9538360.html"}
+			err := NewFiveN1(WithCache(cache)).ScrapeRentalDetail(rental)
+			assert.Nil(t, err)
+			assert.Equal(t, "0980-240-200", rental.Phone)
+		}
+		assert.Equal(t, 1, requested)
+
+		rental := &Rental{URL: svr.URL + "/rent-detail-9538360.html"}
+		err := NewFiveN1(WithCache(cache), WithCacheRefresh()).ScrapeRentalDetail(rental)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, requested, "refresh fetch again")
+	})
+}
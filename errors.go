@@ -0,0 +1,116 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrBlocked mean 591 refuse to serve us, ex: a 403 or a captcha page
+	ErrBlocked = errors.New("blocked by 591")
+	// ErrUnexpectedPage mean the page is not a 591 list or detail page, ex: a maintenance page
+	ErrUnexpectedPage = errors.New("unexpected page")
+)
+
+// RequestError is returned when a request could not reach 591, ex: DNS error, timeout or connection reset
+type RequestError struct {
+	URL string
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("request %s error %v", e.URL, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError is returned when 591 response with a non 2xx status code
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request %s got status %d", e.URL, e.StatusCode)
+}
+
+// ParseError is returned when a response body can not be parsed as HTML
+type ParseError struct {
+	URL string
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse %s error %v", e.URL, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// PageError is returned when 591 serve a page other than what we asked for,
+// Err is either ErrBlocked or ErrUnexpectedPage.
+type PageError struct {
+	URL        string
+	StatusCode int
+	Snippet    string // beginning of the page text
+	Err        error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("%v: %s (status %d): %s", e.Err, e.URL, e.StatusCode, e.Snippet)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// DetailError is returned when the detail page of a rental failed to scrape
+type DetailError struct {
+	Index int // index of the rental in Rentals
+	ID    string
+	URL   string
+	Err   error
+}
+
+func (e *DetailError) Error() string {
+	return fmt.Sprintf("scrape detail %s error %v", e.URL, e.Err)
+}
+
+func (e *DetailError) Unwrap() error {
+	return e.Err
+}
+
+// Errors collect every failed page of a scraping run,
+// it is returned alongside the rentals which did succeed.
+type Errors []error
+
+func (e Errors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d errors occurred: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap let errors.Is and errors.As look into every collected error.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// errorOrNil return nil when nothing is collected, so callers can simply check `err != nil`
+func (e Errors) errorOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
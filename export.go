@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// utf8BOM let Excel open UTF-8 csv with Chinese correctly
+const utf8BOM = "\uFEFF"
+
+// SaveAsCSV save rentals as UTF-8 csv with BOM and a header row like SaveAsXLSX,
+// only columns are written when given.
+func (r Rentals) SaveAsCSV(filename string, columns ...Column) error {
+	columns = orDefault(columns)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("create %s error %v", filename, err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(utf8BOM)
+	if err != nil {
+		return fmt.Errorf("write %s error %v", filename, err)
+	}
+
+	w := csv.NewWriter(file)
+	err = w.Write(csvRecord(columnHeaders(columns)))
+	if err != nil {
+		return fmt.Errorf("csv write error %v", err)
+	}
+	for _, rental := range r {
+		err := w.Write(csvRecord(columnValues(columns, rental)))
+		if err != nil {
+			return fmt.Errorf("csv write error %v", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("csv write error %v", err)
+	}
+
+	return file.Close()
+}
+
+func csvRecord(values []interface{}) []string {
+	record := make([]string, len(values))
+	for i, value := range values {
+		if value != nil {
+			record[i] = fmt.Sprint(value)
+		}
+	}
+
+	return record
+}
+
+// SaveAsJSONL save one JSON object per line which LoadJSONL load back, unlike SaveAsJSON it keeps PostBy and Phone.
+// Every field is written without columns, otherwise only columns are written in their order by Column.Name,
+// with the original text of Price and Ping.
+func (r Rentals) SaveAsJSONL(filename string, columns ...Column) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("create %s error %v", filename, err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for _, rental := range r {
+		line, err := jsonLine(rental, columns)
+		if err != nil {
+			return fmt.Errorf("json encode %s error %v", rental.ID, err)
+		}
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return fmt.Errorf("write %s error %v", filename, err)
+	}
+
+	return file.Close()
+}
+
+// jsonLine encode rental as jsonlRental, keeping only columns when given
+func jsonLine(rental Rental, columns []Column) ([]byte, error) {
+	data, err := json.Marshal(jsonlRental{Rental: rental, PostBy: rental.PostBy, Phone: rental.Phone})
+	if err != nil || len(columns) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, c := range columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(c.Name)
+		b.Write(name)
+		b.WriteByte(':')
+
+		value, ok := fields[c.Name]
+		if !ok {
+			value = json.RawMessage(`""`) // omitted empty PostBy or Phone
+		}
+		b.Write(value)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
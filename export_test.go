@@ -0,0 +1,112 @@
+package scraper
+
+import (
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRentals_SaveAsCSV(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.csv")
+
+	rentals := testRoundTripRentals()
+	assert.Nil(t, rentals.SaveAsCSV(filename))
+
+	data, err := ioutil.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "\uFEFF"), "utf-8 BOM")
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(data), "\uFEFF"))).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, []string{"區", "標題", "類型", "租金", "格局", "坪數", "樓層", "樓", "總樓層", "社區", "聯絡人", "電話", "連結"}, records[0])
+	assert.Equal(t, []string{"中正區", "近捷運套房", "獨立套房", "12000", "1房1廳1衛", "8.5", "樓層：3/5", "3", "5", "君臨天廈", "屋主 王先生", "0912-345-678", "https://rent.591.com.tw/rent-detail-9538360.html"}, records[1])
+	assert.Equal(t, []string{"面議", "", ""}, []string{records[2][3], records[2][7], records[2][8]})
+
+	columns, err := SelectColumns("id", "電話", "price")
+	assert.Nil(t, err)
+	assert.Nil(t, rentals.SaveAsCSV(filename, columns...))
+	data, err = ioutil.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, "\uFEFF編號,電話,租金\nR9538360,0912-345-678,12000\nR9538361,,面議\n", string(data))
+}
+
+func TestRentals_SaveAsJSONL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.jsonl")
+
+	rentals := testRoundTripRentals()
+	assert.Nil(t, rentals.SaveAsJSONL(filename))
+	loaded, err := LoadJSONL(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, rentals, loaded, "PostBy and Phone are kept")
+
+	columns, err := SelectColumns("id", "price", "phone", "floorValue")
+	assert.Nil(t, err)
+	assert.Nil(t, rentals.SaveAsJSONL(filename, columns...))
+	data, err := ioutil.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id":"R9538360","price":"12,000 元/月","phone":"0912-345-678","floorValue":3}`+"\n"+
+		`{"id":"R9538361","price":"面議","phone":"","floorValue":0}`+"\n", string(data))
+
+	loaded, err = LoadJSONL(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, "0912-345-678", loaded[0].Phone)
+	assert.Equal(t, 12000, loaded[0].PriceValue)
+}
+
+func TestRentals_SaveAsXLSX_Columns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.xlsx")
+
+	columns, err := ParseColumns("url,title,address")
+	assert.Nil(t, err)
+	assert.Nil(t, testRoundTripRentals().SaveAsXLSX(filename, columns...))
+
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := f.GetRows(f.GetSheetName(f.GetActiveSheetIndex()))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"連結", "標題", "地址"}, rows[0])
+	assert.Equal(t, []string{"https://rent.591.com.tw/rent-detail-9538361.html", "整棟出租", ""}, rows[2])
+}
+
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns("")
+	assert.Nil(t, err)
+	assert.Len(t, columns, 0)
+	assert.Len(t, DefaultColumns(), 13)
+
+	columns, err = ParseColumns("標題, phone ,連結")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"title", "phone", "url"}, columnNames(columns))
+
+	_, err = ParseColumns("title,size")
+	assert.Regexp(t, `unknown column "size"`, err)
+
+	assert.Len(t, AllColumns(), 16)
+	all := AllColumns()
+	all[0].Name = "changed"
+	assert.Equal(t, "section", AllColumns()[0].Name, "AllColumns return a copy")
+}
@@ -0,0 +1,182 @@
+package scraper
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Response is a page fetched by a Fetcher
+type Response struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	cached bool // served by CachingFetcher from its Cache
+}
+
+// Fetcher fetch url with cookies, FiveN1 use it for both list pages and detail pages.
+// A non 2xx response is not an error for a Fetcher, FiveN1 check StatusCode itself.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string, cookies []*http.Cookie) (*Response, error)
+}
+
+// HTTPFetcher fetch pages through an http.Client
+type HTTPFetcher struct {
+	Client *http.Client
+	Header http.Header // added to every request
+}
+
+func (h *HTTPFetcher) Fetch(ctx context.Context, url string, cookies []*http.Cookie) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, &RequestError{URL: url, Err: err}
+	}
+
+	for key, values := range h.Header {
+		req.Header[key] = values
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	res, err := h.Client.Do(req)
+	if err != nil {
+		return nil, &RequestError{URL: url, Err: err}
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, &RequestError{URL: url, Err: err}
+	}
+
+	return &Response{
+		URL:        url,
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       body,
+	}, nil
+}
+
+// FileFetcher read pages saved under Dir, so archived pages can be parsed offline.
+// A missing file is fetched as 404.
+type FileFetcher struct {
+	Dir  string
+	Name func(url string) string // file name of url, default FileName
+}
+
+func (f *FileFetcher) Fetch(_ context.Context, url string, _ []*http.Cookie) (*Response, error) {
+	name := FileName
+	if f.Name != nil {
+		name = f.Name
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join(f.Dir, name(url)))
+	if os.IsNotExist(err) {
+		return &Response{URL: url, StatusCode: http.StatusNotFound, Header: http.Header{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		URL:        url,
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       body,
+	}, nil
+}
+
+// FileName turn url into a file name, ex:
+// https://rent.591.com.tw/rent-detail-9538360.html become rent.591.com.tw_rent-detail-9538360.html
+func FileName(url string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', '?', '&', '=', ':', '*', '"', '<', '>', '|', '%':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// Cache store responses for CachingFetcher
+type Cache interface {
+	Get(key string) (*Response, bool)
+	Set(key string, res *Response)
+}
+
+// CachingFetcher serve 2xx responses from Cache, other responses and block pages are always fetched again
+type CachingFetcher struct {
+	Fetcher Fetcher
+	Cache   Cache
+	Refresh bool // never read from Cache but still write into it
+}
+
+func (c *CachingFetcher) Fetch(ctx context.Context, url string, cookies []*http.Cookie) (*Response, error) {
+	key := cacheKey(url, cookies)
+	if !c.Refresh {
+		if res, ok := c.Cache.Get(key); ok {
+			hit := *res
+			hit.cached = true
+			return &hit, nil
+		}
+	}
+
+	res, err := c.Fetcher.Fetch(ctx, url, cookies)
+	if err != nil {
+		return nil, err
+	}
+
+	// block pages can be 200 too, they must be fetched again next time,
+	// a listing only mentioning a signature is fetched again too, which is harmless
+	if res.StatusCode >= 200 && res.StatusCode <= 299 && matchSignatures(res.Body) == nil {
+		c.Cache.Set(key, res)
+	}
+
+	return res, nil
+}
+
+// cacheKey is url with its cookies, the same url of different regions are different pages
+func cacheKey(url string, cookies []*http.Cookie) string {
+	key := url
+	for _, cookie := range cookies {
+		key += " " + cookie.Name + "=" + cookie.Value
+	}
+
+	return key
+}
+
+// MemoryCache is a Cache in memory, safe for concurrent use
+type MemoryCache struct {
+	mu        sync.RWMutex
+	responses map[string]*Response
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		responses: map[string]*Response{},
+	}
+}
+
+func (m *MemoryCache) Get(key string) (*Response, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res, ok := m.responses[key]
+	return res, ok
+}
+
+func (m *MemoryCache) Set(key string, res *Response) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.responses[key] = res
+}
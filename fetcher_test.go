@@ -0,0 +1,99 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingFetcher struct {
+	Fetcher
+	fetched int
+}
+
+func (c *countingFetcher) Fetch(ctx context.Context, url string, cookies []*http.Cookie) (*Response, error) {
+	c.fetched++
+	return c.Fetcher.Fetch(ctx, url, cookies)
+}
+
+func TestFileFetcher(t *testing.T) {
+	t.Run("parse saved detail page offline", func(t *testing.T) {
+		fetcher := &FileFetcher{
+			Dir: "test_fixture",
+			Name: func(url string) string {
+				return "591_detail.html"
+			},
+		}
+
+		rental := &Rental{URL: "https://rent.591.com.tw/rent-detail-9538360.html"}
+
+		scraper := NewFiveN1(WithFetcher(fetcher))
+		err := scraper.ScrapeRentalDetail(rental)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "0980-240-200", rental.Phone)
+		assert.Equal(t, "6房3廳4衛4陽台", rental.Layout)
+	})
+
+	t.Run("parse saved list pages offline", func(t *testing.T) {
+		fetcher := &FileFetcher{
+			Dir: "test_fixture",
+			Name: func(url string) string {
+				return "591with120items.html"
+			},
+		}
+
+		scraper := NewFiveN1(WithFetcher(fetcher))
+		rentals, err := scraper.ScrapeRentals(&Query{Region: 1})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 120, len(rentals))
+	})
+
+	t.Run("fetch missing file as 404", func(t *testing.T) {
+		fetcher := &FileFetcher{Dir: "test_fixture"}
+
+		res, err := fetcher.Fetch(context.Background(), "https://rent.591.com.tw/rent-detail-1.html", nil)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "rent.591.com.tw_rent-detail-9538360.html", FileName("https://rent.591.com.tw/rent-detail-9538360.html"))
+	assert.Equal(t, "rent.591.com.tw__region_8_section_98", FileName("https://rent.591.com.tw/?region=8&section=98"))
+}
+
+func TestCachingFetcher(t *testing.T) {
+	status := http.StatusOK
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer svr.Close()
+
+	origin := &countingFetcher{Fetcher: &HTTPFetcher{Client: svr.Client()}}
+	fetcher := &CachingFetcher{Fetcher: origin, Cache: NewMemoryCache()}
+	ctx := context.Background()
+	taipei := []*http.Cookie{regionCookie("1")}
+	taichung := []*http.Cookie{regionCookie("8")}
+
+	res, err := fetcher.Fetch(ctx, svr.URL+"/a", taipei)
+	assert.Nil(t, err)
+	assert.Equal(t, "/a", string(res.Body))
+
+	_, _ = fetcher.Fetch(ctx, svr.URL+"/a", taipei)
+	assert.Equal(t, 1, origin.fetched, "served from cache")
+
+	_, _ = fetcher.Fetch(ctx, svr.URL+"/a", taichung)
+	assert.Equal(t, 2, origin.fetched, "cookies are part of the key")
+
+	status = http.StatusServiceUnavailable
+	_, _ = fetcher.Fetch(ctx, svr.URL+"/b", taipei)
+	_, _ = fetcher.Fetch(ctx, svr.URL+"/b", taipei)
+	assert.Equal(t, 4, origin.fetched, "never cache non 2xx")
+}
@@ -0,0 +1,245 @@
+package scraper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Filter report whether a rental should be kept
+type Filter func(r Rental) bool
+
+// Filter return rentals matching every filter
+func (r Rentals) Filter(filters ...Filter) Rentals {
+	match := And(filters...)
+
+	filtered := Rentals{}
+	for _, rental := range r {
+		if match(rental) {
+			filtered = append(filtered, rental)
+		}
+	}
+
+	return filtered
+}
+
+// And match rentals matching every filter, it matches everything without filters
+func And(filters ...Filter) Filter {
+	return func(r Rental) bool {
+		for _, filter := range filters {
+			if !filter(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or match rentals matching any filter
+func Or(filters ...Filter) Filter {
+	return func(r Rental) bool {
+		for _, filter := range filters {
+			if filter(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not match rentals not matching filter
+func Not(filter Filter) Filter {
+	return func(r Rental) bool {
+		return !filter(r)
+	}
+}
+
+// PriceBetween match PriceValue within min and max inclusive, 0 means no bound.
+// Rentals whose price failed to parse never match.
+func PriceBetween(min, max int) Filter {
+	return func(r Rental) bool {
+		if _, failed := r.ParseErrors["price"]; failed {
+			return false
+		}
+		return (min <= 0 || r.PriceValue >= min) && (max <= 0 || r.PriceValue <= max)
+	}
+}
+
+// PingBetween match PingValue within min and max inclusive, 0 means no bound.
+// Rentals whose ping failed to parse never match.
+func PingBetween(min, max float64) Filter {
+	return func(r Rental) bool {
+		if _, failed := r.ParseErrors["ping"]; failed {
+			return false
+		}
+		return (min <= 0 || r.PingValue >= min) && (max <= 0 || r.PingValue <= max)
+	}
+}
+
+// FloorBetween match FloorValue within min and max inclusive, 0 means no bound as there is no floor 0.
+// Basement floors are negative, ex: FloorBetween(-1, 0) match B1 and above.
+// Rentals without a floor, ex: 整棟, never match.
+func FloorBetween(min, max int) Filter {
+	return func(r Rental) bool {
+		if r.FloorValue == 0 {
+			return false
+		}
+		return (min == 0 || r.FloorValue >= min) && (max == 0 || r.FloorValue <= max)
+	}
+}
+
+// OptionTypeIn match any of option types, ex: 整層住家, 獨立套房
+func OptionTypeIn(optionTypes ...string) Filter {
+	return func(r Rental) bool {
+		return containsString(optionTypes, r.OptionType)
+	}
+}
+
+// SectionIn match any of sections, by code or by name after ReplaceSection
+func SectionIn(sections ...string) Filter {
+	return func(r Rental) bool {
+		return containsString(sections, r.Section)
+	}
+}
+
+// Keyword match rentals with keyword in title or address
+func Keyword(keyword string) Filter {
+	return func(r Rental) bool {
+		return strings.Contains(r.Title, keyword) || strings.Contains(r.Address, keyword)
+	}
+}
+
+// PostByType match rentals posted by any of types, ex: 屋主, 代理人, 仲介
+func PostByType(types ...string) Filter {
+	return func(r Rental) bool {
+		for _, t := range types {
+			if strings.HasPrefix(r.PostBy, t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasPhone match rentals with phone, which is only known after ScrapeRentalsDetail
+func HasPhone() Filter {
+	return func(r Rental) bool {
+		return r.Phone != ""
+	}
+}
+
+// HasCommunity match rentals in a community, which is only known after ScrapeRentalsDetail
+func HasCommunity() Filter {
+	return func(r Rental) bool {
+		return r.Community != ""
+	}
+}
+
+// ParseFilters parse filters separated by ";", ex:
+//
+//	price=10000,20000;ping=8,;floor=2,10;type=整層住家,獨立套房;section=中正區;keyword=捷運;postby=屋主;phone;community
+//
+// ranges are written as Query do, a range without min or max is unbounded on that side.
+func ParseFilters(spec string) ([]Filter, error) {
+	var filters []Filter
+
+	for _, condition := range strings.Split(spec, ";") {
+		condition = strings.TrimSpace(condition)
+		if condition == "" {
+			continue
+		}
+
+		name, value := condition, ""
+		if i := strings.Index(condition, "="); i >= 0 {
+			name, value = strings.TrimSpace(condition[:i]), strings.TrimSpace(condition[i+1:])
+		}
+
+		filter, err := parseFilter(name, value)
+		if err != nil {
+			return nil, fmt.Errorf("filter %q error %v", condition, err)
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func parseFilter(name, value string) (Filter, error) {
+	switch name {
+	case "price":
+		min, max, err := parseRange(value)
+		if err != nil {
+			return nil, err
+		}
+		return PriceBetween(int(min), int(max)), nil
+	case "ping":
+		min, max, err := parseRange(value)
+		if err != nil {
+			return nil, err
+		}
+		return PingBetween(min, max), nil
+	case "floor":
+		min, max, err := parseRange(value)
+		if err != nil {
+			return nil, err
+		}
+		return FloorBetween(int(min), int(max)), nil
+	case "type":
+		return OptionTypeIn(splitList(value)...), nil
+	case "section":
+		return SectionIn(splitList(value)...), nil
+	case "keyword":
+		return Keyword(value), nil
+	case "postby":
+		return PostByType(splitList(value)...), nil
+	case "phone":
+		return HasPhone(), nil
+	case "community":
+		return HasCommunity(), nil
+	}
+
+	return nil, fmt.Errorf("unknown filter %q, valid filters: price, ping, floor, type, section, keyword, postby, phone, community", name)
+}
+
+// parseRange parse "min,max", "min," and ",max", a missing bound is 0
+func parseRange(value string) (min, max float64, err error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("range %q should be min,max", value)
+	}
+	parts[0], parts[1] = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	if parts[0] != "" {
+		if min, err = strconv.ParseFloat(parts[0], 64); err != nil {
+			return 0, 0, fmt.Errorf("range %q min error %v", value, err)
+		}
+	}
+	if parts[1] != "" {
+		if max, err = strconv.ParseFloat(parts[1], 64); err != nil {
+			return 0, 0, fmt.Errorf("range %q max error %v", value, err)
+		}
+	}
+
+	return min, max, nil
+}
+
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
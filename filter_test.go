@@ -0,0 +1,93 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFilterRentals() Rentals {
+	return parsedRentals(Rentals{
+		{ID: "1", Title: "近捷運套房", Address: "中正區", Section: "中正區", OptionType: "獨立套房", Price: "12,000 元/月", Ping: "8坪", Floor: "樓層：3/5", PostBy: "屋主 王先生", Phone: "0912"},
+		{ID: "2", Title: "大安電梯大樓", Address: "大安區復興南路", Section: "大安區", OptionType: "整層住家", Price: "32,000 元/月", Ping: "28坪", Floor: "樓層：B1/12", PostBy: "仲介 陳小姐", Community: "君臨天廈"},
+		{ID: "3", Title: "整棟出租", Address: "中正區", Section: "中正區", OptionType: "整層住家", Price: "面議", Ping: "50坪", Floor: "樓層：整棟/4", PostBy: "代理人 林先生"},
+	})
+}
+
+// parsedRentals return rentals with numbers parsed, as they are scraped or loaded
+func parsedRentals(rentals Rentals) Rentals {
+	for i := range rentals {
+		rentals[i].parseNumbers()
+	}
+
+	return rentals
+}
+
+func rentalIDs(rentals Rentals) []string {
+	ids := []string{}
+	for _, r := range rentals {
+		ids = append(ids, r.ID)
+	}
+
+	return ids
+}
+
+func TestRentals_Filter(t *testing.T) {
+	rentals := testFilterRentals()
+
+	tests := map[string]struct {
+		filters []Filter
+		want    []string
+	}{
+		"no filter":         {nil, []string{"1", "2", "3"}},
+		"price":             {[]Filter{PriceBetween(10000, 20000)}, []string{"1"}},
+		"price no max":      {[]Filter{PriceBetween(20000, 0)}, []string{"2"}},
+		"ping":              {[]Filter{PingBetween(10, 30)}, []string{"2"}},
+		"floor":             {[]Filter{FloorBetween(2, 0)}, []string{"1"}},
+		"basement":          {[]Filter{FloorBetween(0, -1)}, []string{"2"}},
+		"option type":       {[]Filter{OptionTypeIn("整層住家")}, []string{"2", "3"}},
+		"section":           {[]Filter{SectionIn("中正區", "信義區")}, []string{"1", "3"}},
+		"keyword title":     {[]Filter{Keyword("捷運")}, []string{"1"}},
+		"keyword address":   {[]Filter{Keyword("復興南路")}, []string{"2"}},
+		"post by":           {[]Filter{PostByType("屋主", "代理人")}, []string{"1", "3"}},
+		"phone":             {[]Filter{HasPhone()}, []string{"1"}},
+		"community":         {[]Filter{HasCommunity()}, []string{"2"}},
+		"and":               {[]Filter{OptionTypeIn("整層住家"), SectionIn("中正區")}, []string{"3"}},
+		"or":                {[]Filter{Or(HasPhone(), HasCommunity())}, []string{"1", "2"}},
+		"not":               {[]Filter{Not(HasPhone())}, []string{"2", "3"}},
+		"nothing match":     {[]Filter{Keyword("不存在")}, []string{}},
+		"price parse error": {[]Filter{Not(PriceBetween(0, 0))}, []string{"3"}},
+	}
+	for name, test := range tests {
+		assert.Equal(t, test.want, rentalIDs(rentals.Filter(test.filters...)), name)
+	}
+	assert.Len(t, rentals, 3, "Filter should not modify rentals")
+}
+
+func TestParseFilters(t *testing.T) {
+	rentals := testFilterRentals()
+
+	tests := map[string][]string{
+		"":                              {"1", "2", "3"},
+		"price=10000,20000":             {"1"},
+		"price=,20000":                  {"1"},
+		"ping=8.5,":                     {"2", "3"},
+		"floor=-1,1":                    {"2"},
+		"type=整層住家,獨立套房":                {"1", "2", "3"},
+		"section=中正區 ; postby=代理人":      {"3"},
+		"keyword=大安":                    {"2"},
+		"phone":                         {"1"},
+		"community;type=整層住家":           {"2"},
+		"price=0,40000;ping=5,30;phone": {"1"},
+	}
+	for spec, want := range tests {
+		filters, err := ParseFilters(spec)
+		assert.Nil(t, err, spec)
+		assert.Equal(t, want, rentalIDs(rentals.Filter(filters...)), spec)
+	}
+
+	for _, spec := range []string{"unknown=1", "price=abc,", "price=1000", "ping=1,2,3"} {
+		_, err := ParseFilters(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket shared by every request of a FiveN1,
+// list pages and detail pages alike.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second, <= 0 means unlimited
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait block until a token is available or ctx is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	for {
+		wait := l.reserve()
+		if wait == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve take a token and return 0, or return how long to wait for the next token
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = math.Min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// limitingFetcher wait for a token before every fetch, CachingFetcher wrap it so cache hits are never limited
+type limitingFetcher struct {
+	Fetcher Fetcher
+	limiter *rateLimiter
+}
+
+func (l *limitingFetcher) Fetch(ctx context.Context, url string, cookies []*http.Cookie) (*Response, error) {
+	if err := l.limiter.Wait(ctx); err != nil {
+		return nil, &RequestError{URL: url, Err: err}
+	}
+
+	return l.Fetcher.Fetch(ctx, url, cookies)
+}
@@ -0,0 +1,82 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Run("allow burst then wait for tokens", func(t *testing.T) {
+		l := newRateLimiter(20, 2)
+
+		start := time.Now()
+		for i := 0; i < 4; i++ {
+			assert.Nil(t, l.Wait(context.Background()))
+		}
+
+		// 2 burst tokens, then 2 tokens at 20 per second
+		assert.True(t, time.Since(start) >= 90*time.Millisecond, "waited %s", time.Since(start))
+	})
+
+	t.Run("unlimited when rate is not positive", func(t *testing.T) {
+		l := newRateLimiter(0, 1)
+
+		start := time.Now()
+		for i := 0; i < 100; i++ {
+			assert.Nil(t, l.Wait(context.Background()))
+		}
+
+		assert.True(t, time.Since(start) < 50*time.Millisecond)
+	})
+
+	t.Run("return ctx error when canceled", func(t *testing.T) {
+		l := newRateLimiter(0.1, 1)
+		assert.Nil(t, l.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.Equal(t, context.DeadlineExceeded, l.Wait(ctx))
+	})
+}
+
+func TestFiveN1_CacheHitsNotLimited(t *testing.T) {
+	requested := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested++
+		html, _ := ioutil.ReadFile("test_fixture/591_detail.html")
+		_, _ = w.Write(html)
+	}))
+	defer svr.Close()
+
+	newRentals := func() Rentals {
+		var rentals Rentals
+		for i := 0; i < 5; i++ {
+			rentals = append(rentals, Rental{URL: fmt.Sprintf("%s/rent-detail-%d.html", svr.URL, i)})
+		}
+		return rentals
+	}
+
+	cache := NewMemoryCache()
+	err := NewFiveN1(WithCache(cache), WithDelay(0)).ScrapeRentalsDetail(newRentals())
+	assert.Nil(t, err)
+	assert.Equal(t, 5, requested)
+
+	// 1 request per second and a second between detail pages would take 9 seconds without cache
+	f := NewFiveN1(WithCache(cache), WithRateLimit(1, 1), WithDelay(time.Second), WithConcurrency(1))
+	rentals := newRentals()
+	start := time.Now()
+	err = f.ScrapeRentalsDetail(rentals)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, requested, "served from cache")
+	assert.Equal(t, "0980-240-200", rentals[4].Phone)
+	assert.True(t, time.Since(start) < 500*time.Millisecond, "took %s", time.Since(start))
+}
@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"net/http"
+	"time"
+)
+
+// Logger is where FiveN1 report its progress, *log.Logger satisfy it
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Option configure a FiveN1 created by NewFiveN1
+type Option func(f *FiveN1)
+
+// WithHTTPClient replace the default &http.Client{}, ex: to set a timeout or a proxy transport
+func WithHTTPClient(client *http.Client) Option {
+	return func(f *FiveN1) {
+		f.client = client
+	}
+}
+
+// WithFetcher replace the default HTTPFetcher, ex: a FileFetcher to parse archived pages offline.
+// WithHTTPClient, WithUserAgent and WithHeaders only apply to the default HTTPFetcher.
+func WithFetcher(fetcher Fetcher) Option {
+	return func(f *FiveN1) {
+		f.fetcher = fetcher
+	}
+}
+
+// WithDelay set the delay between detail pages, default 10ms, pages served by WithCache are not delayed
+func WithDelay(delay time.Duration) Option {
+	return func(f *FiveN1) {
+		f.delay = delay
+	}
+}
+
+// WithCookies add cookies to every request, besides the urlJumpIp region cookie
+func WithCookies(cookies ...*http.Cookie) Option {
+	return func(f *FiveN1) {
+		f.cookies = append(f.cookies, cookies...)
+	}
+}
+
+// WithUserAgent set User-Agent header of every request
+func WithUserAgent(userAgent string) Option {
+	return func(f *FiveN1) {
+		f.header.Set("User-Agent", userAgent)
+	}
+}
+
+// WithHeaders add headers to every request
+func WithHeaders(header http.Header) Option {
+	return func(f *FiveN1) {
+		for key, values := range header {
+			for _, value := range values {
+				f.header.Add(key, value)
+			}
+		}
+	}
+}
+
+// WithLogger replace the default logger writing to stderr, ex: to silence the scraper
+func WithLogger(logger Logger) Option {
+	return func(f *FiveN1) {
+		f.logger = logger
+	}
+}
+
+// WithConcurrency limit how many pages are requested at the same time, default 5
+func WithConcurrency(n int) Option {
+	return func(f *FiveN1) {
+		if n < 1 {
+			n = 1
+		}
+		f.concurrency = n
+	}
+}
+
+// WithRateLimit limit requests per second with a token bucket shared by list pages and detail pages,
+// burst is how many requests can be sent at once. rps <= 0 disable the limit, which is the default.
+// Pages served by WithCache are not limited.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(f *FiveN1) {
+		f.limiter = newRateLimiter(rps, burst)
+	}
+}
+
+// WithRetryPolicy replace DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(f *FiveN1) {
+		f.retry = policy
+	}
+}
+
+// WithBaseURL set the URL used by queries without RootURL or with the default URL591, ex: NewQuery and presets
+func WithBaseURL(baseURL string) Option {
+	return func(f *FiveN1) {
+		f.baseURL = baseURL
+	}
+}
+
+// WithProgress replace the default progress reporter, which log through the logger every 10 rentals
+func WithProgress(reporter ProgressReporter) Option {
+	return func(f *FiveN1) {
+		f.progress = reporter
+	}
+}
+
+// WithCache serve list pages and detail pages from cache, ex: a DiskCache during development
+func WithCache(cache Cache) Option {
+	return func(f *FiveN1) {
+		f.cache = cache
+	}
+}
+
+// WithCacheRefresh fetch every page again and update the cache given by WithCache
+func WithCacheRefresh() Option {
+	return func(f *FiveN1) {
+		f.refreshCache = true
+	}
+}
@@ -0,0 +1,124 @@
+package scraper
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestNewFiveN1_Options(t *testing.T) {
+	t.Run("default settings", func(t *testing.T) {
+		f := NewFiveN1()
+
+		assert.Equal(t, 10*time.Millisecond, f.delay)
+		assert.Equal(t, defaultConcurrency, f.concurrency)
+		assert.Equal(t, URL591, f.baseURL)
+		assert.Equal(t, DefaultRetryPolicy(), f.retry)
+	})
+
+	t.Run("send user agent, headers and cookies", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "web_scraper/1.0", r.UserAgent())
+			assert.Equal(t, "zh-TW", r.Header.Get("Accept-Language"))
+
+			session, err := r.Cookie("session")
+			assert.Nil(t, err)
+			assert.Equal(t, "abc", session.Value)
+
+			region, err := r.Cookie("urlJumpIp")
+			assert.Nil(t, err)
+			assert.Equal(t, "1", region.Value)
+
+			html, _ := ioutil.ReadFile("test_fixture/591_detail.html")
+			_, _ = w.Write(html)
+		}))
+		defer svr.Close()
+
+		f := NewFiveN1(
+			WithUserAgent("web_scraper/1.0"),
+			WithHeaders(http.Header{"Accept-Language": {"zh-TW"}}),
+			WithCookies(&http.Cookie{Name: "session", Value: "abc"}),
+		)
+
+		err := f.ScrapeRentalDetail(&Rental{URL: svr.URL})
+		assert.Nil(t, err)
+	})
+
+	t.Run("request through the given client", func(t *testing.T) {
+		var gotURL string
+		client := &http.Client{
+			Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				gotURL = r.URL.String()
+				html, _ := ioutil.ReadFile("test_fixture/591with2items.html")
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewReader(html)),
+					Header:     http.Header{},
+					Request:    r,
+				}, nil
+			}),
+		}
+
+		f := NewFiveN1(
+			WithHTTPClient(client),
+			WithBaseURL("https://rent.example.com/"),
+			WithLogger(log.New(ioutil.Discard, "", 0)),
+		)
+		rentals, err := f.ScrapeRentals(&Query{Region: 8})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(rentals))
+		assert.True(t, strings.HasPrefix(gotURL, "https://rent.example.com/?"), gotURL)
+	})
+
+	t.Run("replace URL591 of NewQuery by the base url", func(t *testing.T) {
+		var gotURL string
+		client := &http.Client{
+			Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				gotURL = r.URL.String()
+				html, _ := ioutil.ReadFile("test_fixture/591with2items.html")
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewReader(html)),
+					Header:     http.Header{},
+					Request:    r,
+				}, nil
+			}),
+		}
+
+		f := NewFiveN1(
+			WithHTTPClient(client),
+			WithBaseURL("https://rent.example.com/"),
+			WithLogger(log.New(ioutil.Discard, "", 0)),
+		)
+		rentals, err := f.ScrapeRentals(NewQuery())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(rentals))
+		assert.True(t, strings.HasPrefix(gotURL, "https://rent.example.com/?"), gotURL)
+	})
+
+	t.Run("log through the given logger", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(item120Handler))
+		defer server.Close()
+
+		buf := &bytes.Buffer{}
+		f := NewFiveN1(WithLogger(log.New(buf, "", 0)))
+		_, _ = f.ScrapeRentals(&Query{RootURL: server.URL + "/?"})
+
+		assert.Contains(t, buf.String(), "Total Record: 120")
+	})
+}
@@ -0,0 +1,95 @@
+package scraper
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// snippetLength is how many characters of a page are kept in PageError
+const snippetLength = 200
+
+// blockSignatures are found in pages 591 serve instead of listings when we are blocked
+var blockSignatures = []string{
+	"captcha",
+	"驗證碼",
+	"access denied",
+	"請求過於頻繁",
+	"訪問異常",
+}
+
+// maintenanceSignatures are found in pages 591 serve when the site is down
+var maintenanceSignatures = []string{
+	"系統維護",
+	"維護中",
+	"maintenance",
+}
+
+// checkPage return a PageError when res is a 403, other non 2xx responses are left to StatusError
+func checkPage(res *Response) error {
+	if res.StatusCode == http.StatusForbidden {
+		return newPageError(res, ErrBlocked)
+	}
+
+	return nil
+}
+
+// expectPage return a PageError when doc has nothing matching selector,
+// ex: a list page without the records number is not a list page at all.
+// Block and maintenance signatures are only searched then, so a listing titled 維護中 is still a listing.
+func expectPage(res *Response, doc *goquery.Document, selector string) error {
+	if doc.Find(selector).Length() == 0 {
+		err := matchSignatures(res.Body)
+		if err == nil {
+			err = ErrUnexpectedPage
+		}
+		return newPageError(res, err)
+	}
+
+	return nil
+}
+
+// matchSignatures return ErrBlocked for a block page, ErrUnexpectedPage for a maintenance page, otherwise nil
+func matchSignatures(body []byte) error {
+	body = bytes.ToLower(body)
+	for _, signature := range blockSignatures {
+		if bytes.Contains(body, []byte(signature)) {
+			return ErrBlocked
+		}
+	}
+	for _, signature := range maintenanceSignatures {
+		if bytes.Contains(body, []byte(signature)) {
+			return ErrUnexpectedPage
+		}
+	}
+
+	return nil
+}
+
+func newPageError(res *Response, err error) *PageError {
+	return &PageError{
+		URL:        res.URL,
+		StatusCode: res.StatusCode,
+		Snippet:    snippet(res.Body),
+		Err:        err,
+	}
+}
+
+// snippet return the beginning of the text in body, whitespace collapsed
+func snippet(body []byte) string {
+	text := string(body)
+	if doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body)); err == nil {
+		doc.Find("script, style").Remove()
+		text = doc.Text()
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= snippetLength {
+		return text
+	}
+
+	return string([]rune(text)[:snippetLength]) + "…"
+}
@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiveN1_DetectUnexpectedPage(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{"403", http.StatusForbidden, "<html><body>Forbidden</body></html>", ErrBlocked},
+		{"captcha page", http.StatusOK, `<html><body><div class="g-recaptcha"></div>請輸入驗證碼</body></html>`, ErrBlocked},
+		{"maintenance page", http.StatusOK, "<html><body>591 系統維護中，請稍後再試</body></html>", ErrUnexpectedPage},
+		{"empty page", http.StatusOK, "", ErrUnexpectedPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			scraper := NewFiveN1(WithRetryPolicy(fastRetryPolicy()))
+
+			rentals, err := scraper.ScrapeRentals(&Query{RootURL: server.URL + "/?"})
+			assert.True(t, errors.Is(err, tt.wantErr), "list page got %v", err)
+			assert.Equal(t, 0, len(rentals))
+
+			err = scraper.ScrapeRentalDetail(&Rental{URL: server.URL + "/rent-detail-1.html"})
+			assert.True(t, errors.Is(err, tt.wantErr), "detail page got %v", err)
+
+			var pageErr *PageError
+			assert.True(t, errors.As(err, &pageErr))
+			assert.Equal(t, server.URL+"/rent-detail-1.html", pageErr.URL)
+			assert.Equal(t, tt.status, pageErr.StatusCode)
+		})
+	}
+}
+
+func TestFiveN1_SignatureInListing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		html, _ := ioutil.ReadFile("test_fixture/591with2items_maintenance_title.html")
+		_, _ = w.Write(html)
+	}))
+	defer server.Close()
+
+	scraper := NewFiveN1(WithRetryPolicy(fastRetryPolicy()))
+
+	rentals, err := scraper.ScrapeRentals(&Query{RootURL: server.URL + "/?"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rentals))
+	assert.Equal(t, "稀有花園別墅⭐電梯維護中⭐雙平車⭐可寵", rentals[0].Title)
+}
+
+func TestSnippet(t *testing.T) {
+	body := "<html><head><script>var a = 1;</script></head><body>\n  請輸入   驗證碼\n</body></html>"
+	assert.Equal(t, "請輸入 驗證碼", snippet([]byte(body)))
+
+	long := strings.Repeat("維", snippetLength+10)
+	assert.Equal(t, strings.Repeat("維", snippetLength)+"…", snippet([]byte(long)))
+}
@@ -0,0 +1,134 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	numberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+	floorPattern  = regexp.MustCompile(`^(B?)(\d+)F?$`)
+)
+
+// parseNumbers fill PriceValue, PingValue and floor fields from Price, Ping and Floor,
+// what can't be parsed is recorded into ParseErrors and its value is left zero.
+func (r *Rental) parseNumbers() {
+	r.ParseErrors = nil
+
+	if price, err := parsePrice(r.Price); err != nil {
+		r.addParseError("price", err)
+	} else {
+		r.PriceValue = price
+	}
+
+	if ping, err := parsePing(r.Ping); err != nil {
+		r.addParseError("ping", err)
+	} else {
+		r.PingValue = ping
+	}
+
+	if floor, err := parseFloor(r.Floor); err != nil {
+		r.addParseError("floor", err)
+	} else {
+		r.FloorValue = floor.floor
+		r.TotalFloors = floor.total
+		r.Basement = floor.basement
+		r.Rooftop = floor.rooftop
+	}
+}
+
+func (r *Rental) addParseError(field string, err error) {
+	if r.ParseErrors == nil {
+		r.ParseErrors = map[string]string{}
+	}
+	r.ParseErrors[field] = err.Error()
+}
+
+// parsePrice parse NT$ per month, ex: "12,000 元 / 月" is 12000
+func parsePrice(s string) (int, error) {
+	number := numberPattern.FindString(strings.Replace(s, ",", "", -1))
+	if number == "" {
+		return 0, fmt.Errorf("no price in %q", s)
+	}
+
+	price, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse price %q error %v", s, err)
+	}
+
+	return int(price), nil
+}
+
+// parsePing parse area in ping, ex: "8.5坪" is 8.5
+func parsePing(s string) (float64, error) {
+	number := numberPattern.FindString(s)
+	if number == "" {
+		return 0, fmt.Errorf("no ping in %q", s)
+	}
+
+	ping, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse ping %q error %v", s, err)
+	}
+
+	return ping, nil
+}
+
+type floor struct {
+	floor    int // basement floors are negative, ex: B1 is -1
+	total    int
+	basement bool
+	rooftop  bool // 頂樓加蓋, floor is the one above total
+}
+
+// parseFloor parse "樓層：3/5", "樓層：B1/5", "樓層：頂樓加蓋/5" and "樓層 :  3F/15F",
+// "樓層：整棟/4" and "樓層：整棟" are the whole building thus have no floor.
+func parseFloor(s string) (floor, error) {
+	var f floor
+
+	value := strings.Replace(s, "：", ":", -1)
+	if i := strings.Index(value, ":"); i >= 0 {
+		value = value[i+1:]
+	}
+	value = strings.Join(strings.Fields(value), "")
+
+	if value == "整棟" {
+		return f, nil
+	}
+
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return f, fmt.Errorf("unknown floor %q", s)
+	}
+
+	total, err := strconv.Atoi(strings.TrimSuffix(parts[1], "F"))
+	if err != nil {
+		return f, fmt.Errorf("unknown total floors %q", s)
+	}
+	f.total = total
+
+	current := parts[0]
+	if current == "整棟" {
+		return f, nil
+	}
+	if strings.Contains(current, "加蓋") {
+		f.floor = total + 1
+		f.rooftop = true
+		return f, nil
+	}
+
+	match := floorPattern.FindStringSubmatch(current)
+	if match == nil {
+		return f, fmt.Errorf("unknown floor %q", s)
+	}
+
+	f.floor, _ = strconv.Atoi(match[2])
+	if match[1] == "B" {
+		f.floor = -f.floor
+		f.basement = true
+	}
+
+	return f, nil
+}
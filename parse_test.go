@@ -0,0 +1,116 @@
+package scraper
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePrice(t *testing.T) {
+	tests := map[string]int{
+		"12,000 元/月":    12000,
+		"48,000 元 / 月":  48000,
+		"7,899 元 / 月":   7899,
+		"NT$ 15000 / 月": 15000,
+	}
+	for s, want := range tests {
+		got, err := parsePrice(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	_, err := parsePrice("面議")
+	assert.NotNil(t, err)
+}
+
+func TestParsePing(t *testing.T) {
+	tests := map[string]float64{
+		"8.5坪": 8.5,
+		"128":  128,
+		"50.8": 50.8,
+	}
+	for s, want := range tests {
+		got, err := parsePing(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	_, err := parsePing("沒有格局說明")
+	assert.NotNil(t, err)
+}
+
+func TestParseFloor(t *testing.T) {
+	tests := map[string]floor{
+		"樓層：3/5":       {floor: 3, total: 5},
+		"樓層：13/28":     {floor: 13, total: 28},
+		"樓層：B1/5":      {floor: -1, total: 5, basement: true},
+		"樓層：頂樓加蓋/5":    {floor: 6, total: 5, rooftop: true},
+		"樓層 :  3F/15F": {floor: 3, total: 15},
+		"樓層：整棟":        {},
+		"樓層：整棟/4":      {total: 4},
+	}
+	for s, want := range tests {
+		got, err := parseFloor(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	_, err := parseFloor("樓層：")
+	assert.NotNil(t, err)
+	_, err = parseFloor("樓層：三樓/5")
+	assert.NotNil(t, err)
+}
+
+func TestParseRentHouse_Floor(t *testing.T) {
+	html, err := ioutil.ReadFile("test_fixture/591with2items.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		floor     string
+		wantFloor string
+		want      floor
+	}{
+		{"樓層：整棟/4", "樓層：整棟/4", floor{total: 4}},
+		{"樓層：頂樓加蓋/5", "樓層：頂樓加蓋/5", floor{floor: 6, total: 5, rooftop: true}},
+		{"樓層：B1/5", "樓層：B1/5", floor{floor: -1, total: 5, basement: true}},
+	}
+	for _, tt := range tests {
+		page := strings.Replace(string(html), "樓層：整棟/4", tt.floor, 1)
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rental := parseRentHouse(doc)[0]
+		assert.Equal(t, tt.wantFloor, rental.Floor)
+		assert.Empty(t, rental.ParseErrors["floor"], tt.floor)
+		assert.Equal(t, tt.want.floor, rental.FloorValue, tt.floor)
+		assert.Equal(t, tt.want.total, rental.TotalFloors, tt.floor)
+		assert.Equal(t, tt.want.rooftop, rental.Rooftop, tt.floor)
+		assert.Equal(t, tt.want.basement, rental.Basement, tt.floor)
+	}
+}
+
+func TestRental_parseNumbers(t *testing.T) {
+	rental := &Rental{
+		Price: "面議",
+		Ping:  "8.5坪",
+		Floor: "樓層：B1/5",
+	}
+
+	rental.parseNumbers()
+
+	assert.Equal(t, 0, rental.PriceValue)
+	assert.Equal(t, 8.5, rental.PingValue)
+	assert.Equal(t, -1, rental.FloorValue)
+	assert.Equal(t, 5, rental.TotalFloors)
+	assert.True(t, rental.Basement)
+	assert.Len(t, rental.ParseErrors, 1)
+	assert.Contains(t, rental.ParseErrors["price"], "面議")
+}
@@ -0,0 +1,203 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named search saved in a profiles file, ex: profiles.yaml
+//
+//	taichung:
+//	  region: 台中市
+//	  sections: [西屯區, 南屯區]
+//	  kind: 0
+//	  price: 10000,20000
+//	  owner: true
+//	  filters: ["ping=8,"]
+//	  output:
+//	    formats: [xlsx, csv]
+type Profile struct {
+	Name     string   `json:"-" yaml:"-" toml:"-"`                      // key of the profile in its file
+	Region   string   `json:"region" yaml:"region" toml:"region"`       // code or name, ex: 8 or 台中市
+	Sections []string `json:"sections" yaml:"sections" toml:"sections"` // names or codes, every section of the region when empty
+	Kind     Kind     `json:"kind" yaml:"kind" toml:"kind"`             // Query.Kind, 0 is 不限
+	Price    string   `json:"price" yaml:"price" toml:"price"`          // 租金 min,max, ex: "10000,20000" or ",20000"
+	Area     string   `json:"area" yaml:"area" toml:"area"`             // 坪數 min,max, ex: "8,"
+	Options  []string `json:"options" yaml:"options" toml:"options"`    // Query.Option, ex: [cold, washer]
+	Others   []string `json:"others" yaml:"others" toml:"others"`       // Query.Other, ex: [pet, cook]
+	Owner    bool     `json:"owner" yaml:"owner" toml:"owner"`          // only rentals posted by owners
+	Filters  []string `json:"filters" yaml:"filters" toml:"filters"`    // conditions of ParseFilters, ex: ["ping=8,", "postby=屋主"]
+	Sort     []string `json:"sort" yaml:"sort" toml:"sort"`             // keys of ParseSortKeys, ex: [section, -ping]
+	Detail   *bool    `json:"detail" yaml:"detail" toml:"detail"`       // scrape detail pages, default true
+	Output   Output   `json:"output" yaml:"output" toml:"output"`
+}
+
+// Output is where and how rentals of a profile are saved
+type Output struct {
+	Dir       string   `json:"dir" yaml:"dir" toml:"dir"`                   // default "."
+	Name      string   `json:"name" yaml:"name" toml:"name"`                // filename without extension, default "{date}-{profile}"
+	Formats   []string `json:"formats" yaml:"formats" toml:"formats"`       // xlsx, csv, jsonl and json, default xlsx
+	Columns   []string `json:"columns" yaml:"columns" toml:"columns"`       // names or headers of SelectColumns, default columns when empty
+	BySection bool     `json:"bySection" yaml:"bySection" toml:"bySection"` // save xlsx by SaveAsXLSXBySection
+}
+
+// Profiles is profiles of a file by name
+type Profiles map[string]Profile
+
+// LoadProfiles load profiles by extension of filename, .yaml, .yml, .toml or .json.
+// Every profile is validated and unknown keys are errors, so typos don't silently widen a search.
+func LoadProfiles(filename string) (Profiles, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read %s error %v", filename, err)
+	}
+
+	var profiles Profiles
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&profiles)
+	case ".toml":
+		var meta toml.MetaData
+		meta, err = toml.Decode(string(data), &profiles)
+		if undecoded := meta.Undecoded(); err == nil && len(undecoded) > 0 {
+			err = fmt.Errorf("unknown key %s", undecoded[0])
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&profiles)
+	default:
+		return nil, fmt.Errorf("unknown profiles file %s, should be .yaml, .yml, .toml or .json", filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode %s error %v", filename, err)
+	}
+
+	for name, profile := range profiles {
+		profile.Name = name
+		profiles[name] = profile
+	}
+	for _, name := range profiles.Names() {
+		err := profiles[name].Validate()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+	}
+
+	return profiles, nil
+}
+
+// Names return names of profiles in order
+func (p Profiles) Names() []string {
+	var names []string
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get return profiles by names in order, with an error listing valid names for an unknown one
+func (p Profiles) Get(names ...string) ([]Profile, error) {
+	var profiles []Profile
+	for _, name := range names {
+		profile, ok := p[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q, valid profiles: %s", name, strings.Join(p.Names(), ", "))
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+// Validate check every field of the profile could be used, the query is checked by Query.Validate
+func (p Profile) Validate() error {
+	q, err := p.Query()
+	if err == nil {
+		err = q.Validate()
+	}
+	if err != nil {
+		return fmt.Errorf("profile %q error %v", p.Name, err)
+	}
+
+	_, err = ParseFilters(strings.Join(p.Filters, ";"))
+	if err != nil {
+		return fmt.Errorf("profile %q error %v", p.Name, err)
+	}
+	_, err = ParseSortKeys(strings.Join(p.Sort, ","))
+	if err != nil {
+		return fmt.Errorf("profile %q error %v", p.Name, err)
+	}
+	_, err = p.Output.SelectColumns()
+	if err != nil {
+		return fmt.Errorf("profile %q error %v", p.Name, err)
+	}
+	for _, format := range p.Output.Formats {
+		switch format {
+		case "xlsx", "csv", "jsonl", "json":
+		default:
+			return fmt.Errorf("profile %q unknown format %q, valid formats: xlsx, csv, jsonl and json", p.Name, format)
+		}
+	}
+
+	return nil
+}
+
+// Query create the `Query` of the profile, its region and sections are resolved by name
+func (p Profile) Query() (*Query, error) {
+	if strings.TrimSpace(p.Region) == "" {
+		return nil, fmt.Errorf("region is required")
+	}
+	region, err := FindRegion(p.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := region.Sections
+	if len(p.Sections) > 0 {
+		sections, err = region.SectionCodes(p.Sections)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	q := NewQuery()
+	q.Region = region.Code
+	q.Section = strings.Join(sections, ",")
+	q.Kind = p.Kind
+	q.RentPrice = RentPrice(strings.ReplaceAll(p.Price, " ", ""))
+	q.Area = strings.ReplaceAll(p.Area, " ", "")
+	q.Option = strings.Join(p.Options, ",")
+	q.Other = strings.Join(p.Others, ",")
+	if p.Owner {
+		q.Role = "1"
+	}
+
+	return q, nil
+}
+
+// ScrapeDetail report whether detail pages should be scraped, which is the default
+func (p Profile) ScrapeDetail() bool {
+	return p.Detail == nil || *p.Detail
+}
+
+// SelectColumns return columns of the output, nil for default columns
+func (o Output) SelectColumns() ([]Column, error) {
+	if len(o.Columns) == 0 {
+		return nil, nil
+	}
+
+	return SelectColumns(o.Columns...)
+}
@@ -0,0 +1,146 @@
+package scraper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testProfiles = map[string]string{
+	"profiles.yaml": `
+taichung:
+  region: 臺中市
+  sections: [西屯區, 104, 南屯區]
+  price: 10000,20000
+  options: [cold, washer]
+  owner: true
+  detail: false
+  output:
+    formats: [xlsx, csv]
+    bySection: true
+taipei:
+  region: 1
+`,
+	"profiles.toml": `
+[taichung]
+region = "臺中市"
+sections = ["西屯區", "104", "南屯區"]
+price = "10000,20000"
+options = ["cold", "washer"]
+owner = true
+detail = false
+
+[taichung.output]
+formats = ["xlsx", "csv"]
+bySection = true
+
+[taipei]
+region = "1"
+`,
+	"profiles.json": `{
+  "taichung": {
+    "region": "臺中市",
+    "sections": ["西屯區", "104", "南屯區"],
+    "price": "10000,20000",
+    "options": ["cold", "washer"],
+    "owner": true,
+    "detail": false,
+    "output": {"formats": ["xlsx", "csv"], "bySection": true}
+  },
+  "taipei": {"region": "1"}
+}`,
+}
+
+func writeProfiles(t *testing.T, dir, filename, content string) string {
+	filename = filepath.Join(dir, filename)
+	err := ioutil.WriteFile(filename, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filename
+}
+
+func TestLoadProfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for filename, content := range testProfiles {
+		profiles, err := LoadProfiles(writeProfiles(t, dir, filename, content))
+		if !assert.Nil(t, err, filename) {
+			continue
+		}
+		assert.Equal(t, []string{"taichung", "taipei"}, profiles.Names(), filename)
+
+		taichung := profiles["taichung"]
+		assert.Equal(t, "taichung", taichung.Name, filename)
+		assert.False(t, taichung.ScrapeDetail(), filename)
+		assert.Equal(t, Output{Formats: []string{"xlsx", "csv"}, BySection: true}, taichung.Output, filename)
+
+		q, err := taichung.Query()
+		assert.Nil(t, err, filename)
+		assert.Equal(t, 8, q.Region, filename)
+		assert.Equal(t, "104,104,105", q.Section, filename)
+		assert.Equal(t, KindAny, q.Kind, filename)
+		assert.Equal(t, RentPriceRange(10000, 20000), q.RentPrice, filename)
+		assert.Equal(t, "cold,washer", q.Option, filename)
+		assert.Equal(t, "1", q.Role, filename)
+
+		taipei := profiles["taipei"]
+		assert.True(t, taipei.ScrapeDetail(), filename)
+		q, err = taipei.Query()
+		assert.Nil(t, err, filename)
+		assert.Equal(t, "1,2,3,4,5,6,7,8,9,10,11,12", q.Section, filename)
+		assert.Equal(t, RentPrice(""), q.RentPrice, filename)
+		assert.Equal(t, "", q.Role, filename)
+	}
+}
+
+func TestLoadProfiles_Errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		filename, content, err string
+	}{
+		{"typo.yaml", "a:\n  region: 1\n  prce: 1,2\n", "field prce not found"},
+		{"typo.toml", "[a]\nregion = \"1\"\nprce = \"1,2\"\n", "unknown key a.prce"},
+		{"typo.json", `{"a": {"region": "1", "prce": "1,2"}}`, `unknown field "prce"`},
+		{"region.yaml", "a:\n  kind: 1\n", `profile "a" error region is required`},
+		{"region.yaml", "a:\n  region: 9\n", `profile "a" error unknown region "9"`},
+		{"section.yaml", "a:\n  region: 台北市\n  sections: [西屯區]\n", `profile "a" error section "西屯區" is not in 台北市, valid sections: 中正區, 大同區`},
+		{"price.yaml", "a:\n  region: 1\n  price: 20000,10000\n", `profile "a" error rentprice range "20000,10000" is inverted, min 20000 is greater than max 10000`},
+		{"area.yaml", "a:\n  region: 1\n  area: ten\n", `profile "a" error area range "ten" should be min,max`},
+		{"filter.yaml", "a:\n  region: 1\n  filters: [size=1]\n", `profile "a" error filter "size=1"`},
+		{"sort.yaml", "a:\n  region: 1\n  sort: [size]\n", `profile "a" error unknown sort key "size"`},
+		{"columns.yaml", "a:\n  region: 1\n  output:\n    columns: [size]\n", `profile "a" error unknown column "size"`},
+		{"formats.yaml", "a:\n  region: 1\n  output:\n    formats: [pdf]\n", `profile "a" unknown format "pdf"`},
+		{"profiles.ini", "", "unknown profiles file"},
+	}
+	for _, test := range tests {
+		_, err := LoadProfiles(writeProfiles(t, dir, test.filename, test.content))
+		if assert.NotNil(t, err, test.filename) {
+			assert.Contains(t, err.Error(), test.err, test.filename)
+		}
+	}
+}
+
+func TestProfiles_Get(t *testing.T) {
+	profiles := Profiles{"a": {Name: "a"}, "b": {Name: "b"}}
+
+	got, err := profiles.Get("b", "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []Profile{{Name: "b"}, {Name: "a"}}, got)
+
+	_, err = profiles.Get("c")
+	assert.EqualError(t, err, `unknown profile "c", valid profiles: a, b`)
+}
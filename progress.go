@@ -0,0 +1,74 @@
+package scraper
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress of scraping rental details
+type Progress struct {
+	Done    int // rentals finished, including failed ones
+	Failed  int
+	Total   int
+	Elapsed time.Duration
+	ETA     time.Duration // estimated time left, 0 until the first rental is done
+}
+
+// ProgressReporter receive Progress after each detail page, Report is never called concurrently
+type ProgressReporter interface {
+	Report(p Progress)
+}
+
+// ProgressFunc adapt a function to ProgressReporter
+type ProgressFunc func(p Progress)
+
+func (fn ProgressFunc) Report(p Progress) {
+	fn(p)
+}
+
+// LogProgress report progress through logger every n rentals and when all rentals are done
+func LogProgress(logger Logger, every int) ProgressReporter {
+	if every < 1 {
+		every = 1
+	}
+
+	return ProgressFunc(func(p Progress) {
+		if p.Done%every != 0 && p.Done != p.Total {
+			return
+		}
+		logger.Printf("# Detail: %d/%d | Failed: %d | Elapsed: %s | ETA: %s\n",
+			p.Done, p.Total, p.Failed, p.Elapsed.Round(time.Second), p.ETA.Round(time.Second))
+	})
+}
+
+// progressTracker count finished rentals and report to a ProgressReporter
+type progressTracker struct {
+	mu       sync.Mutex
+	reporter ProgressReporter
+	start    time.Time
+	progress Progress
+}
+
+func newProgressTracker(total int, reporter ProgressReporter) *progressTracker {
+	return &progressTracker{
+		reporter: reporter,
+		start:    time.Now(),
+		progress: Progress{Total: total},
+	}
+}
+
+// done mark a rental finished, failed when err is not nil
+func (t *progressTracker) done(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := &t.progress
+	p.Done++
+	if err != nil {
+		p.Failed++
+	}
+	p.Elapsed = time.Since(t.start)
+	p.ETA = p.Elapsed / time.Duration(p.Done) * time.Duration(p.Total-p.Done)
+
+	t.reporter.Report(*p)
+}
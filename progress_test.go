@@ -0,0 +1,27 @@
+package scraper
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogProgress(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tracker := newProgressTracker(5, LogProgress(log.New(buf, "", 0), 2))
+
+	tracker.done(nil)
+	tracker.done(errors.New("404"))
+	tracker.done(nil)
+	tracker.done(nil)
+	tracker.done(nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3, "log every 2 rentals and the last one")
+	assert.True(t, strings.HasPrefix(lines[0], "# Detail: 2/5 | Failed: 1 |"), lines[0])
+	assert.True(t, strings.HasPrefix(lines[2], "# Detail: 5/5 | Failed: 1 |"), lines[2])
+}
@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,3 +20,74 @@ func TestURL(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, gotURL, wantURL)
 }
+
+func TestURL_Codes(t *testing.T) {
+	q := &Query{
+		RootURL:     URL591,
+		Region:      8,
+		Kind:        KindWholeFloor,
+		RentPrice:   RentPriceRange(10000, 0),
+		Shape:       ShapeElevator,
+		PatternMore: Patterns(PatternTwoRooms, PatternThreeRooms),
+		Floor:       Floor2To6,
+		Option:      Options(OptionAirCon, OptionWasher),
+		Other:       Others(OtherPet),
+	}
+
+	gotURL, err := q.URL()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://rent.591.com.tw/?firstRow=0&floor=2%2C6&kind=1&option=cold%2Cwasher&order=&orderType=&other=pet&patternMore=2%2C3&region=8&rentprice=10000%2C&shape=2", gotURL)
+}
+
+func TestQuery_Validate(t *testing.T) {
+	for _, q := range []*Query{NewQuery(), QueryMini(), QueryTaiChung(), QueryTaipei()} {
+		assert.Nil(t, q.Validate())
+	}
+
+	q := QueryTaiChung()
+	q.RentPrice = RentPrice5kTo10k
+	q.Floor = FloorAbove12
+	q.Pattern = PatternAny
+	q.Option = Options(OptionTV, OptionSofa)
+	assert.Nil(t, q.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(q *Query)
+		err    string
+	}{
+		{"region", func(q *Query) { q.Region = 9 }, `unknown region "9", valid regions: 1 台北市`},
+		{"section", func(q *Query) { q.Section = "98,1" }, `section "1" is not in 台中市, valid sections: 98 中區, 99 東區`},
+		{"kind", func(q *Query) { q.Kind = 5 }, `unknown kind "5", valid values: 0 不限, 1 整層住家, 2 獨立套房, 3 分租套房, 4 雅房, 8 車位, 24 其他`},
+		{"rentprice code", func(q *Query) { q.RentPrice = "9" }, `unknown rentprice "9", valid values: 2 5k-10k, 3 10k-20k, 4 20k-30k or a range min,max`},
+		{"rentprice inverted", func(q *Query) { q.RentPrice = RentPriceRange(20000, 10000) }, `rentprice range "20000,10000" is inverted, min 20000 is greater than max 10000`},
+		{"area", func(q *Query) { q.Area = "ten,20" }, `area range "ten,20" min error`},
+		{"floor", func(q *Query) { q.Floor = "-1,3" }, `floor range "-1,3" should not be negative`},
+		{"order", func(q *Query) { q.Order = "price" }, `unknown order "price", valid values: posttime 刊登時間, money 租金`},
+		{"sex", func(q *Query) { q.Sex = 3 }, `unknown sex "3"`},
+		{"role", func(q *Query) { q.Role = "0" }, `unknown role "0", valid values: 1 是`},
+		{"shape", func(q *Query) { q.Shape = "5" }, `unknown shape "5", valid values: 1 公寓, 2 電梯大樓, 3 透天厝, 4 別墅`},
+		{"patternMore", func(q *Query) { q.PatternMore = "1,6" }, `unknown patternMore "6"`},
+		{"option", func(q *Query) { q.Option = "cold,aircon" }, `unknown option "aircon", valid values: tv 電視, cold 冷氣`},
+		{"other", func(q *Query) { q.Other = "pets" }, `unknown other "pets", valid values: cartplace 有車位`},
+		{"firstRow", func(q *Query) { q.FirstRow = -30 }, `firstRow -30 should not be negative`},
+	}
+	for _, test := range tests {
+		q := QueryTaiChung()
+		test.modify(q)
+		err := q.Validate()
+		if assert.NotNil(t, err, test.name) {
+			assert.Contains(t, err.Error(), test.err, test.name)
+		}
+	}
+
+	q = QueryTaiChung()
+	q.Kind = 5
+	q.Other = "pets"
+	err := q.Validate()
+	var errs Errors
+	if assert.True(t, errors.As(err, &errs)) {
+		assert.Len(t, errs, 2)
+	}
+}
@@ -0,0 +1,159 @@
+package scraper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Region is a city or county of 591 with codes of all its sections
+type Region struct {
+	Code     int
+	Name     string
+	Sections []string
+}
+
+var regions = []Region{
+	{Code: 1, Name: "台北市", Sections: sectionRange(1, 12)},
+	{Code: 2, Name: "基隆市", Sections: sectionRange(13, 19)},
+	{Code: 3, Name: "新北市", Sections: append(sectionRange(20, 21), sectionRange(26, 52)...)},
+	{Code: 4, Name: "新竹市", Sections: sectionRange(370, 372)},
+	{Code: 5, Name: "新竹縣", Sections: sectionRange(54, 66)},
+	{Code: 6, Name: "桃園市", Sections: sectionRange(67, 79)},
+	{Code: 7, Name: "苗栗縣", Sections: sectionRange(80, 97)},
+	{Code: 8, Name: "台中市", Sections: sectionRange(98, 126)},
+	{Code: 10, Name: "彰化縣", Sections: sectionRange(127, 152)},
+	{Code: 11, Name: "南投縣", Sections: sectionRange(153, 165)},
+	{Code: 12, Name: "嘉義市", Sections: sectionRange(373, 374)},
+	{Code: 13, Name: "嘉義縣", Sections: sectionRange(167, 183)},
+	{Code: 14, Name: "雲林縣", Sections: sectionRange(185, 204)},
+	{Code: 15, Name: "台南市", Sections: sectionRange(206, 242)},
+	{Code: 17, Name: "高雄市", Sections: sectionRange(243, 282)},
+	{Code: 19, Name: "屏東縣", Sections: sectionRange(295, 327)},
+	{Code: 21, Name: "宜蘭縣", Sections: sectionRange(328, 339)},
+	{Code: 22, Name: "台東縣", Sections: sectionRange(341, 356)},
+	{Code: 23, Name: "花蓮縣", Sections: sectionRange(357, 369)},
+	{Code: 24, Name: "澎湖縣", Sections: sectionRange(283, 288)},
+	{Code: 25, Name: "金門縣", Sections: sectionRange(289, 294)},
+	{Code: 26, Name: "連江縣", Sections: sectionRange(22, 25)},
+}
+
+func sectionRange(from, to int) []string {
+	var codes []string
+	for code := from; code <= to; code++ {
+		codes = append(codes, strconv.Itoa(code))
+	}
+
+	return codes
+}
+
+// Regions return all regions ordered by code
+func Regions() []Region {
+	all := make([]Region, len(regions))
+	for i, r := range regions {
+		all[i] = r
+		all[i].Sections = append([]string{}, r.Sections...)
+	}
+
+	return all
+}
+
+// FindRegion find a region by its code or name, ex: "8", "台中市" or "臺中"
+func FindRegion(region string) (Region, error) {
+	region = strings.TrimSpace(region)
+	name := strings.ReplaceAll(region, "臺", "台")
+	var matches []Region
+	for _, r := range Regions() {
+		if strconv.Itoa(r.Code) == region || r.Name == name {
+			return r, nil
+		}
+		if strings.TrimRight(r.Name, "市縣") == name {
+			matches = append(matches, r)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		return Region{}, fmt.Errorf("region %q is ambiguous, use %s or %s", region, matches[0].Name, matches[1].Name)
+	}
+
+	var valid []string
+	for _, r := range regions {
+		valid = append(valid, fmt.Sprintf("%d %s", r.Code, r.Name))
+	}
+	return Region{}, fmt.Errorf("unknown region %q, valid regions: %s", region, strings.Join(valid, ", "))
+}
+
+// SectionName return the name of a section code, "" for unknown codes
+func SectionName(code string) string {
+	return sectionDict[code]
+}
+
+// sectionRegion find the region of a section code or name,
+// a name shared by several regions, ex: 東區, is not found
+func sectionRegion(section string) (Region, bool) {
+	section = strings.ReplaceAll(strings.TrimSpace(section), "臺", "台")
+	if section == "" {
+		return Region{}, false
+	}
+
+	var matches []Region
+	for _, r := range regions {
+		for _, code := range r.Sections {
+			if code == section {
+				return r, true
+			}
+			if strings.ReplaceAll(sectionDict[code], "臺", "台") == section {
+				matches = append(matches, r)
+			}
+		}
+	}
+
+	if len(matches) != 1 {
+		return Region{}, false
+	}
+	return matches[0], true
+}
+
+// SectionCodes convert section names or codes of the region into codes, ex: "西屯區" into "104"
+func (r Region) SectionCodes(sections []string) ([]string, error) {
+	var codes []string
+	for _, section := range sections {
+		section = strings.TrimSpace(section)
+		name := strings.ReplaceAll(section, "臺", "台")
+
+		found := ""
+		for _, code := range r.Sections {
+			if code == section || strings.ReplaceAll(sectionDict[code], "臺", "台") == name {
+				found = code
+				break
+			}
+		}
+		if found == "" {
+			return nil, fmt.Errorf("section %q is not in %s, valid sections: %s", section, r.Name, strings.Join(r.sectionNames(), ", "))
+		}
+		codes = append(codes, found)
+	}
+
+	return codes, nil
+}
+
+// validSections list codes with names of sections, ex: "1 中正區, 2 大同區"
+func (r Region) validSections() string {
+	var valid []string
+	for _, code := range r.Sections {
+		valid = append(valid, code+" "+sectionDict[code])
+	}
+
+	return strings.Join(valid, ", ")
+}
+
+func (r Region) sectionNames() []string {
+	var names []string
+	for _, code := range r.Sections {
+		names = append(names, sectionDict[code])
+	}
+
+	return names
+}
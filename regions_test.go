@@ -0,0 +1,44 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRegion(t *testing.T) {
+	for _, region := range []string{"8", "台中市", "臺中市", "台中", " 8 "} {
+		r, err := FindRegion(region)
+		assert.Nil(t, err, region)
+		assert.Equal(t, 8, r.Code, region)
+		assert.Equal(t, "台中市", r.Name, region)
+	}
+
+	r, err := FindRegion("3")
+	assert.Nil(t, err)
+	assert.Equal(t, "新北市", r.Name)
+	assert.Equal(t, []string{"20", "21", "26"}, r.Sections[:3])
+
+	_, err = FindRegion("新竹")
+	assert.EqualError(t, err, `region "新竹" is ambiguous, use 新竹市 or 新竹縣`)
+
+	_, err = FindRegion("9")
+	assert.Contains(t, err.Error(), `unknown region "9", valid regions: 1 台北市, 2 基隆市`)
+}
+
+func TestRegions(t *testing.T) {
+	all := Regions()
+	assert.Len(t, all, 22)
+
+	seen := map[string]string{}
+	for _, r := range all {
+		for _, code := range r.Sections {
+			assert.NotEmpty(t, SectionName(code), "%s section %s", r.Name, code)
+			assert.Empty(t, seen[code], "section %s of %s is also in %s", code, r.Name, seen[code])
+			seen[code] = r.Name
+		}
+	}
+
+	all[0].Sections[0] = "changed"
+	assert.Equal(t, "1", Regions()[0].Sections[0])
+}
@@ -1,10 +1,15 @@
 package scraper
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
 )
 
 // Rental represent a rental house
@@ -18,7 +23,8 @@ type Rental struct {
 	Phone  string `json:"-"`     //聯絡電話
 	Price  string `json:"price"` // 租金
 
-	Section    string `json:"section"` //行政區
+	Region     int    `json:"region,omitempty"` // 縣市 code of the query listing the rental, 0 when unknown
+	Section    string `json:"section"`          //行政區
 	Address    string `json:"address"`
 	Community  string `json:"community"`  // 社區名 ex: 君臨天廈
 	OptionType string `json:"optionType"` // 獨立套房、整層住家… etc
@@ -26,6 +32,15 @@ type Rental struct {
 	Floor      string `json:"floor"`      //樓層
 	Layout     string `json:"layout"`     // 格局, ex: 3房2廳2衛2陽台
 
+	// parsed from Price, Ping and Floor, fields failed to parse are recorded in ParseErrors and left zero
+	PriceValue  int               `json:"priceValue"`  // 租金 NT$ / 月
+	PingValue   float64           `json:"pingValue"`   // 坪數
+	FloorValue  int               `json:"floorValue"`  // 樓層, B1 is -1, 頂樓加蓋 is TotalFloors + 1
+	TotalFloors int               `json:"totalFloors"` // 總樓層
+	Basement    bool              `json:"basement"`
+	Rooftop     bool              `json:"rooftop"`               // 頂樓加蓋
+	ParseErrors map[string]string `json:"parseErrors,omitempty"` // field name to parse error, ex: "price"
+
 	//Preview    string `json:"preview"` // preview image
 	//RentType   string `json:"rentType"`   // 以代號儲存的格局，不轉換的話沒有用
 	//IsNew      bool   `json:"isNew"`
@@ -45,6 +60,15 @@ func (r *Rentals) Print() {
 	}
 }
 
+// numberOrText return number unless field failed to parse, then return its original text
+func (r Rental) numberOrText(field string, number interface{}, text string) interface{} {
+	if _, failed := r.ParseErrors[field]; failed {
+		return text
+	}
+
+	return number
+}
+
 // ReplaceSection replace all section code with section name
 func (r *Rentals) ReplaceSection() {
 	for i, rental := range *r {
@@ -58,33 +82,214 @@ func (r Rentals) SaveAsJSON(filename string) error {
 	if err != nil {
 		return fmt.Errorf("create %s error %v", filename, err)
 	}
+	defer file.Close()
 
 	err = json.NewEncoder(file).Encode(r)
 	if err != nil {
 		return fmt.Errorf("json encode error %v", err)
 	}
 
-	return nil
+	return file.Close()
+}
+
+// LoadRentals load rentals by extension of filename, .json, .jsonl or .xlsx
+func LoadRentals(filename string) (Rentals, error) {
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".json":
+		return LoadJSON(filename)
+	case ".jsonl":
+		return LoadJSONL(filename)
+	case ".xlsx":
+		return LoadXLSX(filename)
+	default:
+		return nil, fmt.Errorf("unknown rentals file %s, should be .json, .jsonl or .xlsx", filename)
+	}
+}
+
+// LoadJSON load rentals saved by SaveAsJSON, numbers are parsed again from Price, Ping and Floor
+// so files saved before they existed have them too.
+func LoadJSON(filename string) (Rentals, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open %s error %v", filename, err)
+	}
+	defer file.Close()
+
+	var rentals Rentals
+	err = json.NewDecoder(file).Decode(&rentals)
+	if err != nil {
+		return nil, fmt.Errorf("json decode %s error %v", filename, err)
+	}
+
+	for i := range rentals {
+		rentals[i].parseNumbers()
+	}
+
+	return rentals, nil
+}
+
+// jsonlRental is a line of JSONL, unlike SaveAsJSON it keeps PostBy and Phone
+type jsonlRental struct {
+	Rental
+	PostBy string `json:"postBy,omitempty"`
+	Phone  string `json:"phone,omitempty"`
 }
 
-//區	標題	類型	租金	格局	坪數	樓層	社區	聯絡人	電話	連結
-func (r Rentals) SaveAsXLSX(filename string) error {
-	x := newXlsx()
-	err := x.WriteNextRow("區", "標題", "類型", "租金", "格局", "坪數", "樓層", "社區", "聯絡人", "電話", "連結")
+// LoadJSONL load rentals of one JSON object per line, blank lines are skipped.
+// Numbers are parsed again from Price, Ping and Floor.
+func LoadJSONL(filename string) (Rentals, error) {
+	file, err := os.Open(filename)
 	if err != nil {
-		return fmt.Errorf("xlsx.WriteNextRow error %v", err)
+		return nil, fmt.Errorf("open %s error %v", filename, err)
+	}
+	defer file.Close()
+
+	rentals := Rentals{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var j jsonlRental
+		err := json.Unmarshal(scanner.Bytes(), &j)
+		if err != nil {
+			return nil, fmt.Errorf("json decode %s line %d error %v", filename, line, err)
+		}
+		rental := j.Rental
+		rental.PostBy, rental.Phone = j.PostBy, j.Phone
+		rental.parseNumbers()
+		rentals = append(rentals, rental)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s error %v", filename, err)
+	}
+
+	return rentals, nil
+}
+
+//區	標題	類型	租金	格局	坪數	樓層	樓	總樓層	社區	聯絡人	電話	連結
+// 租金, 坪數, 樓 and 總樓層 are written as numbers, the original text is written when it failed to parse.
+// Only columns are written when given, ex: SelectColumns("title", "price", "phone")
+// Rows are streamed by XLSXWriter so large rentals don't build the workbook in memory,
+// links are written as plain text when there are more rentals than a sheet could link.
+func (r Rentals) SaveAsXLSX(filename string, columns ...Column) error {
+	columns = orDefault(columns)
+
+	w, err := newXLSXWriter(filename, columns, r.displayWidths(columns))
+	if err != nil {
+		return err
+	}
+	if len(r) > maxHyperLinks {
+		w.maxLinks = 0
 	}
 	for _, rental := range r {
-		err := x.WriteNextRow(rental.Section, rental.Title, rental.OptionType, rental.Price, rental.Layout, rental.Ping, rental.Floor, rental.Community, rental.PostBy, rental.Phone, rental.URL)
+		err := w.Write(rental)
 		if err != nil {
-			return fmt.Errorf("xlsx.WriteNextRow error %v", err)
+			return err
 		}
 	}
 
-	err = x.Save(filename)
+	err = w.Close()
 	if err != nil {
 		return fmt.Errorf("xlsx save file error %v", err)
 	}
 
 	return nil
 }
+
+// displayWidths return display width of the widest value or header of columns
+func (r Rentals) displayWidths(columns []Column) []int {
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = displayWidth(c.Header)
+	}
+	for _, rental := range r {
+		for i, value := range columnValues(columns, rental) {
+			if w := cellWidth(value); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	return widths
+}
+
+// writeXLSX write header and rentals into the current sheet of x
+func (r Rentals) writeXLSX(x *xlsx, columns []Column) error {
+	err := x.WriteHeader(columnHeaders(columns)...)
+	if err != nil {
+		return fmt.Errorf("xlsx.WriteHeader error %v", err)
+	}
+	setNumberFormats(x, columns, 0)
+	for _, rental := range r {
+		err := x.WriteNextRow(xlsxValues(columns, rental)...)
+		if err != nil {
+			return fmt.Errorf("xlsx.WriteNextRow error %v", err)
+		}
+	}
+
+	return nil
+}
+
+// LoadXLSX load rentals of every sheet whose first row has 標題 or 連結 header written by SaveAsXLSX,
+// so the 總覽 sheet of SaveAsXLSXBySection is skipped. Columns are mapped by header so their order doesn't matter and unknown columns are skipped.
+// ID comes from 連結, 租金 and 坪數 written as numbers come back as their number text.
+func LoadXLSX(filename string) (Rentals, error) {
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open %s error %v", filename, err)
+	}
+
+	rentals := Rentals{}
+	found := false
+	for _, sheet := range f.GetSheetList() {
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("read sheet %s of %s error %v", sheet, filename, err)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		cols := columnsByHeader(rows[0])
+		if !isRentalSheet(cols) {
+			continue
+		}
+		found = true
+
+		for _, row := range rows[1:] {
+			if strings.TrimSpace(strings.Join(row, "")) == "" {
+				continue
+			}
+
+			var rental Rental
+			for i, value := range row {
+				if i < len(cols) && cols[i] != nil && cols[i].Set != nil {
+					cols[i].Set(&rental, value)
+				}
+			}
+			if rental.ID == "" {
+				rental.ID = idFromURL(rental.URL)
+			}
+			rental.parseNumbers()
+			rentals = append(rentals, rental)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no rental headers in %s", filename)
+	}
+
+	return rentals, nil
+}
+
+func isRentalSheet(cols []*Column) bool {
+	for _, c := range cols {
+		if c != nil && (c.Name == "title" || c.Name == "url") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,253 @@
+package scraper
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// xlsxCell return the raw xml of a cell in the first sheet of an xlsx file
+func xlsxCell(t *testing.T, filename string, axis string) string {
+	data := xlsxPart(t, filename, "xl/worksheets/sheet1.xml")
+
+	return regexp.MustCompile(`<c r="` + axis + `"[^>]*>.*?</c>|<c r="` + axis + `"[^>]*/>`).FindString(data)
+}
+
+// xlsxPart return the raw xml of a part of an xlsx file, ex: xl/styles.xml
+func xlsxPart(t *testing.T, filename string, name string) string {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+		rc, _ := f.Open()
+		defer rc.Close()
+		data, _ := ioutil.ReadAll(rc)
+
+		return string(data)
+	}
+
+	return ""
+}
+
+func TestRentals_SaveAsXLSX(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rentals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.xlsx")
+
+	parsed := Rental{Price: "12,000 元/月", Ping: "8.5", Floor: "樓層：3/5"}
+	parsed.parseNumbers()
+	failed := Rental{Price: "面議", Ping: "8", Floor: "樓層：整棟"}
+	failed.parseNumbers()
+
+	err = Rentals{parsed, failed}.SaveAsXLSX(filename)
+	assert.Nil(t, err)
+
+	assert.Regexp(t, `<v>12000</v>`, xlsxCell(t, filename, "D2"))
+	assert.NotRegexp(t, `t="`, xlsxCell(t, filename, "D2"), "price is a number")
+	assert.Regexp(t, `<v>8.5</v>`, xlsxCell(t, filename, "F2"))
+	assert.Regexp(t, `<v>3</v>`, xlsxCell(t, filename, "H2"))
+	assert.Regexp(t, `<v>5</v>`, xlsxCell(t, filename, "I2"))
+	assert.Regexp(t, `t="str"`, xlsxCell(t, filename, "D3"), "price failed to parse is text")
+}
+
+func TestRentals_SaveAsXLSX_Style(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rentals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.xlsx")
+
+	err = testRoundTripRentals().SaveAsXLSX(filename)
+	assert.Nil(t, err)
+
+	sheet := xlsxPart(t, filename, "xl/worksheets/sheet1.xml")
+	assert.Regexp(t, `<pane [^>]*state="frozen"[^>]*topLeftCell="A2"`, sheet, "header is frozen")
+	assert.Regexp(t, `<autoFilter ref="A1:M3"`, sheet)
+	assert.Regexp(t, `<hyperlink ref="M2"`, sheet, "url is a hyperlink")
+	assert.Regexp(t, `<col [^>]*max="2" min="2" width="12"`, sheet, "近捷運套房 is 10 wide")
+	assert.Regexp(t, `<col [^>]*max="13" min="13" width="50"`, sheet)
+	assert.Regexp(t, `<col [^>]*max="8" min="8" width="8"`, sheet, "min width")
+
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	link, target, err := f.GetCellHyperLink("Sheet1", "M3")
+	assert.Nil(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://rent.591.com.tw/rent-detail-9538361.html", target)
+
+	styles := xlsxPart(t, filename, "xl/styles.xml")
+	assert.Regexp(t, `<numFmt numFmtId="164" formatCode="#,##0">`, styles, "price has thousands separator")
+	assert.Regexp(t, `s="[1-9]`, xlsxCell(t, filename, "D2"))
+	assert.NotRegexp(t, `s="`, xlsxCell(t, filename, "D3"), "price failed to parse is not formatted")
+	assert.Regexp(t, `formatCode="0.0#"`, styles, "ping format")
+	assert.Regexp(t, `<b>true</b>`, styles, "header is bold")
+	assert.Regexp(t, `s="[1-9]`, xlsxCell(t, filename, "A1"))
+	assert.NotRegexp(t, `s="`, xlsxCell(t, filename, "A2"))
+}
+
+func TestLoadJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rentals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.json")
+
+	old := testRoundTripRentals()
+	assert.Nil(t, old.SaveAsJSON(filename))
+
+	loaded, err := LoadJSON(filename)
+	assert.Nil(t, err)
+	// SaveAsJSON doesn't save PostBy and Phone
+	for i := range old {
+		old[i].PostBy, old[i].Phone = "", ""
+	}
+	assert.Equal(t, old, loaded)
+
+	// saved before numbers were parsed
+	assert.Nil(t, ioutil.WriteFile(filename, []byte(`[{"id":"1","price":"12,000 元/月"}]`), 0644))
+	loaded, err = LoadJSON(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, 12000, loaded[0].PriceValue)
+
+	_, err = LoadJSON(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+}
+
+func testRoundTripRentals() Rentals {
+	return parsedRentals(Rentals{
+		{ID: "R9538360", Title: "近捷運套房", URL: "https://rent.591.com.tw/rent-detail-9538360.html", Region: 1, Section: "中正區", OptionType: "獨立套房",
+			Price: "12,000 元/月", Ping: "8.5坪", Floor: "樓層：3/5", Layout: "1房1廳1衛", Community: "君臨天廈", PostBy: "屋主 王先生", Phone: "0912-345-678"},
+		{ID: "R9538361", Title: "整棟出租", URL: "https://rent.591.com.tw/rent-detail-9538361.html", Section: "大安區", OptionType: "整層住家",
+			Price: "面議", Ping: "50", Floor: "樓層：整棟"},
+	})
+}
+
+func TestLoadJSONL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rentals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.jsonl")
+
+	rentals := testRoundTripRentals()
+	var lines []string
+	for _, rental := range rentals {
+		line, err := json.Marshal(jsonlRental{Rental: rental, PostBy: rental.PostBy, Phone: rental.Phone})
+		assert.Nil(t, err)
+		lines = append(lines, string(line), "")
+	}
+	assert.Nil(t, ioutil.WriteFile(filename, []byte(strings.Join(lines, "\n")), 0644))
+
+	loaded, err := LoadJSONL(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, rentals, loaded)
+
+	assert.Nil(t, ioutil.WriteFile(filename, []byte("{\"id\":\"1\"}\nnot json\n"), 0644))
+	_, err = LoadJSONL(filename)
+	assert.Regexp(t, "line 2", err)
+}
+
+func TestLoadXLSX(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rentals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.xlsx")
+
+	rentals := testRoundTripRentals()
+	assert.Nil(t, rentals.SaveAsXLSX(filename, AllColumns()...))
+
+	loaded, err := LoadXLSX(filename)
+	assert.Nil(t, err)
+	assert.Len(t, loaded, 2)
+
+	// 租金 and 坪數 are written as numbers, so only their numbers come back
+	for i := range rentals {
+		want := rentals[i]
+		if _, failed := want.ParseErrors["price"]; !failed {
+			want.Price = strconv.Itoa(want.PriceValue)
+		}
+		want.Ping = strconv.FormatFloat(want.PingValue, 'f', -1, 64)
+		assert.Equal(t, want, loaded[i])
+	}
+	assert.Equal(t, "面議", loaded[1].Price)
+
+	_, err = LoadXLSX(filepath.Join(dir, "missing.xlsx"))
+	assert.NotNil(t, err)
+}
+
+func TestLoadXLSX_Headers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rentals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.xlsx")
+
+	f := excelize.NewFile()
+	f.NewSheet("summary")
+	assert.Nil(t, f.SetSheetRow("summary", "A1", &[]interface{}{"區", "數量"}))
+	assert.Nil(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"連結", "備註", "標題", "租金"}))
+	assert.Nil(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"https://rent.591.com.tw/rent-detail-1.html", "note", "套房", 8000}))
+	assert.Nil(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"", "", "雅房"}))
+	assert.Nil(t, f.SaveAs(filename))
+
+	loaded, err := LoadXLSX(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"R1", ""}, rentalIDs(loaded))
+	assert.Equal(t, "套房", loaded[0].Title)
+	assert.Equal(t, 8000, loaded[0].PriceValue)
+	assert.Equal(t, "雅房", loaded[1].Title)
+	assert.Equal(t, "", loaded[1].Section, "summary sheet has no rental")
+
+	f = excelize.NewFile()
+	assert.Nil(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"名稱"}))
+	assert.Nil(t, f.SaveAs(filename))
+	_, err = LoadXLSX(filename)
+	assert.NotNil(t, err)
+}
+
+func TestLoadRentals(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rentals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rentals := testRoundTripRentals()
+	assert.Nil(t, rentals.SaveAsJSON(filepath.Join(dir, "rentals.json")))
+	assert.Nil(t, rentals.SaveAsXLSX(filepath.Join(dir, "rentals.XLSX")))
+
+	for _, name := range []string{"rentals.json", "rentals.XLSX"} {
+		loaded, err := LoadRentals(filepath.Join(dir, name))
+		assert.Nil(t, err, name)
+		assert.Equal(t, rentalIDs(rentals), rentalIDs(loaded), name)
+	}
+
+	_, err = LoadRentals(filepath.Join(dir, "rentals.csv"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,134 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decide how a failed request is retried,
+// delay before the nth retry is BaseDelay * 2^(n-1), capped by MaxDelay and randomized by Jitter.
+type RetryPolicy struct {
+	MaxAttempts     int           // attempts including the first request, <= 1 means never retry
+	BaseDelay       time.Duration // delay before the first retry
+	MaxDelay        time.Duration // upper bound of a delay, also bound the server's Retry-After, 0 means no bound
+	Jitter          float64       // 0 ~ 1, delay is randomized within ±Jitter
+	RetryableStatus []int         // status codes worth to retry, ex: 429, 503
+}
+
+// DefaultRetryPolicy retry 3 times on network errors and on 429, 500, 502, 503, 504
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// RetryError is returned when every attempt of a request failed
+type RetryError struct {
+	URL      string
+	Attempts int
+	Err      error // error of the last attempt
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("request %s failed after %d attempts: %v", e.URL, e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// retryable report whether err is worth another attempt, a timeout of http.Client is a RequestError worth it.
+// Whether the caller's ctx is done is checked by FiveN1.request, which is the only reason to stop on a ctx error.
+func (p RetryPolicy) retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		for _, code := range p.RetryableStatus {
+			if statusErr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	var requestErr *RequestError
+	return errors.As(err, &requestErr)
+}
+
+// backoff return the delay before the given retry, retry starts from 1.
+// retryAfter from the server is preferred when it is given.
+func (p RetryPolicy) backoff(retry int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return retryAfter
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delta := float64(delay) * p.Jitter
+		delay = time.Duration(float64(delay) - delta + rand.Float64()*2*delta)
+	}
+
+	return delay
+}
+
+// parseRetryAfter parse Retry-After header in seconds or HTTP-date, return 0 when absent or invalid
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// sleep wait d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,188 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fastRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+
+	return policy
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1, 0))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2, 0))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3, 0))
+	assert.Equal(t, time.Second, policy.backoff(10, 0), "capped by MaxDelay")
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(1, 300*time.Millisecond), "prefer Retry-After")
+	assert.Equal(t, time.Second, policy.backoff(1, time.Hour), "Retry-After capped by MaxDelay")
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(1, 0)
+		assert.True(t, delay >= 50*time.Millisecond && delay <= 150*time.Millisecond, "delay %s", delay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	got := parseRetryAfter(date)
+	assert.True(t, got > 50*time.Second && got <= time.Minute, "got %s", got)
+}
+
+func TestFiveN1_Retry(t *testing.T) {
+	t.Run("retry retryable status until success", func(t *testing.T) {
+		requested := 0
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			if requested < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			html, _ := ioutil.ReadFile("test_fixture/591_detail.html")
+			_, _ = w.Write(html)
+		}))
+		defer svr.Close()
+
+		rental := &Rental{URL: svr.URL + "/rent-detail-9538360.html"}
+
+		scraper := NewFiveN1(WithRetryPolicy(fastRetryPolicy()))
+		err := scraper.ScrapeRentalDetail(rental)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, requested)
+		assert.Equal(t, "0980-240-200", rental.Phone)
+	})
+
+	t.Run("retry timeouts of the http client", func(t *testing.T) {
+		requested := 0
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			if requested < 3 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			html, _ := ioutil.ReadFile("test_fixture/591_detail.html")
+			_, _ = w.Write(html)
+		}))
+		defer svr.Close()
+
+		rental := &Rental{URL: svr.URL + "/rent-detail-9538360.html"}
+
+		scraper := NewFiveN1(WithRetryPolicy(fastRetryPolicy()), WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}))
+		err := scraper.ScrapeRentalDetail(rental)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, requested)
+		assert.Equal(t, "0980-240-200", rental.Phone)
+	})
+
+	t.Run("stop retrying once ctx is done", func(t *testing.T) {
+		requested := 0
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer svr.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		scraper := NewFiveN1(WithRetryPolicy(fastRetryPolicy()))
+		err := scraper.ScrapeRentalDetailContext(ctx, &Rental{URL: svr.URL + "/rent-detail-9538360.html"})
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+		assert.Equal(t, 1, requested)
+	})
+
+	t.Run("return RetryError with attempts when retries exhausted", func(t *testing.T) {
+		requested := 0
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer svr.Close()
+
+		rental := &Rental{URL: svr.URL + "/rent-detail-9538360.html"}
+
+		policy := fastRetryPolicy()
+		policy.MaxAttempts = 3
+		scraper := NewFiveN1(WithRetryPolicy(policy))
+		err := scraper.ScrapeRentalDetail(rental)
+
+		var retryErr *RetryError
+		assert.True(t, errors.As(err, &retryErr))
+		assert.Equal(t, 3, retryErr.Attempts)
+		assert.Equal(t, rental.URL, retryErr.URL)
+		var statusErr *StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+		assert.Equal(t, 3, requested)
+	})
+
+	t.Run("never retry non retryable status", func(t *testing.T) {
+		requested := 0
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer svr.Close()
+
+		rental := &Rental{URL: svr.URL + "/rent-detail-9538360.html"}
+
+		scraper := NewFiveN1(WithRetryPolicy(fastRetryPolicy()))
+		err := scraper.ScrapeRentalDetail(rental)
+
+		var statusErr *StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		var retryErr *RetryError
+		assert.False(t, errors.As(err, &retryErr))
+		assert.Equal(t, 1, requested)
+	})
+
+	t.Run("honor Retry-After", func(t *testing.T) {
+		requested := 0
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			if requested == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			html, _ := ioutil.ReadFile("test_fixture/591_detail.html")
+			_, _ = w.Write(html)
+		}))
+		defer svr.Close()
+
+		rental := &Rental{URL: svr.URL + "/rent-detail-9538360.html"}
+
+		policy := fastRetryPolicy()
+		policy.MaxDelay = 0
+		scraper := NewFiveN1(WithRetryPolicy(policy))
+
+		start := time.Now()
+		err := scraper.ScrapeRentalDetail(rental)
+
+		assert.Nil(t, err)
+		assert.True(t, time.Since(start) >= time.Second, "took %s", time.Since(start))
+	})
+}
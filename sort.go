@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKey compare two rentals, return a negative number when a should come before b,
+// a positive number when b should come before a, and 0 when they are equal.
+type SortKey func(a, b Rental) int
+
+// Sort sort rentals by keys in order, later keys break ties of earlier keys.
+// Rentals equal on every key keep their order.
+func (r Rentals) Sort(keys ...SortKey) {
+	sort.SliceStable(r, func(i, j int) bool {
+		for _, key := range keys {
+			if c := key(r[i], r[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// Desc reverse key
+func Desc(key SortKey) SortKey {
+	return func(a, b Rental) int {
+		return key(b, a)
+	}
+}
+
+// ByPrice sort by PriceValue, cheapest first
+func ByPrice(a, b Rental) int {
+	return compareInt(a.PriceValue, b.PriceValue)
+}
+
+// ByPing sort by PingValue, smallest first
+func ByPing(a, b Rental) int {
+	return compareFloat(a.PingValue, b.PingValue)
+}
+
+// ByPricePerPing sort by price per ping, cheapest first, rentals without ping come last
+func ByPricePerPing(a, b Rental) int {
+	if a.PingValue == 0 || b.PingValue == 0 {
+		return compareFloat(b.PingValue, a.PingValue)
+	}
+
+	return compareFloat(float64(a.PriceValue)/a.PingValue, float64(b.PriceValue)/b.PingValue)
+}
+
+// ByFloor sort by FloorValue, lowest first
+func ByFloor(a, b Rental) int {
+	return compareInt(a.FloorValue, b.FloorValue)
+}
+
+// BySection sort by Section
+func BySection(a, b Rental) int {
+	return strings.Compare(a.Section, b.Section)
+}
+
+// ByOptionType sort by OptionType
+func ByOptionType(a, b Rental) int {
+	return strings.Compare(a.OptionType, b.OptionType)
+}
+
+// ByID sort by ID
+func ByID(a, b Rental) int {
+	return strings.Compare(a.ID, b.ID)
+}
+
+// sortKeys are SortKey by name for ParseSortKeys
+var sortKeys = map[string]SortKey{
+	"price":        ByPrice,
+	"ping":         ByPing,
+	"pricePerPing": ByPricePerPing,
+	"floor":        ByFloor,
+	"section":      BySection,
+	"type":         ByOptionType,
+	"id":           ByID,
+}
+
+// ParseSortKeys parse sort keys separated by ",", a leading "-" sort descending, ex: "section,-ping,price"
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	var keys []SortKey
+
+	for _, name := range splitList(spec) {
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+
+		key, ok := sortKeys[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort key %q, valid keys: price, ping, pricePerPing, floor, section, type, id", name)
+		}
+		if desc {
+			key = Desc(key)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRentals_Sort(t *testing.T) {
+	rentals := Rentals{
+		{ID: "1", Section: "大安區", PriceValue: 20000, PingValue: 10, FloorValue: 3},
+		{ID: "2", Section: "中正區", PriceValue: 10000, PingValue: 10, FloorValue: -1},
+		{ID: "3", Section: "大安區", PriceValue: 15000, PingValue: 20, FloorValue: 5},
+		{ID: "4", Section: "中正區", PriceValue: 10000, PingValue: 5, FloorValue: 2},
+	}
+
+	rentals.Sort(ByPrice)
+	assert.Equal(t, []string{"2", "4", "3", "1"}, rentalIDs(rentals), "stable for equal prices")
+
+	rentals.Sort(Desc(ByPing), ByPrice)
+	assert.Equal(t, []string{"3", "2", "1", "4"}, rentalIDs(rentals))
+
+	rentals.Sort(BySection, Desc(ByFloor))
+	assert.Equal(t, []string{"4", "2", "3", "1"}, rentalIDs(rentals))
+
+	rentals.Sort(ByPricePerPing)
+	assert.Equal(t, []string{"3", "2", "4", "1"}, rentalIDs(rentals))
+
+	rentals.Sort()
+	assert.Equal(t, []string{"3", "2", "4", "1"}, rentalIDs(rentals), "no key keep order")
+}
+
+func TestParseSortKeys(t *testing.T) {
+	rentals := Rentals{
+		{ID: "1", Section: "大安區", PriceValue: 20000},
+		{ID: "2", Section: "中正區", PriceValue: 10000},
+		{ID: "3", Section: "大安區", PriceValue: 15000},
+	}
+
+	keys, err := ParseSortKeys("section, -price")
+	assert.Nil(t, err)
+	rentals.Sort(keys...)
+	assert.Equal(t, []string{"2", "1", "3"}, rentalIDs(rentals))
+
+	keys, err = ParseSortKeys("")
+	assert.Nil(t, err)
+	assert.Len(t, keys, 0)
+
+	_, err = ParseSortKeys("price,size")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,265 @@
+package scraper
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // pure go sqlite driver
+)
+
+const storeSchema = `
+CREATE TABLE IF NOT EXISTS rentals (
+	id           TEXT PRIMARY KEY,
+	title        TEXT NOT NULL,
+	url          TEXT NOT NULL,
+	post_by      TEXT NOT NULL,
+	phone        TEXT NOT NULL,
+	price        TEXT NOT NULL,
+	section      TEXT NOT NULL,
+	address      TEXT NOT NULL,
+	community    TEXT NOT NULL,
+	option_type  TEXT NOT NULL,
+	ping         TEXT NOT NULL,
+	floor        TEXT NOT NULL,
+	layout       TEXT NOT NULL,
+	scope        TEXT NOT NULL DEFAULT '',
+	price_value  INTEGER NOT NULL,
+	ping_value   REAL NOT NULL,
+	first_seen   INTEGER NOT NULL,
+	last_seen    INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS rentals_last_seen ON rentals (last_seen);
+CREATE TABLE IF NOT EXISTS runs (
+	scope   TEXT NOT NULL,
+	seen_at INTEGER NOT NULL,
+	PRIMARY KEY (scope, seen_at)
+);
+CREATE TABLE IF NOT EXISTS prices (
+	id          TEXT NOT NULL REFERENCES rentals (id),
+	price       TEXT NOT NULL,
+	price_value INTEGER NOT NULL,
+	seen_at     INTEGER NOT NULL,
+	PRIMARY KEY (id, seen_at)
+);
+`
+
+const rentalColumns = "id, title, url, post_by, phone, price, section, address, community, option_type, ping, floor, layout, first_seen, last_seen"
+
+// Store keep history of rentals in a sqlite database, times are stored as unix seconds.
+// Rentals are saved under a scope, which is the key of a search, ex: a region or a profile name,
+// so searches saved into one database don't delist each other's rentals.
+// A rental belong to the scope which saved it last.
+type Store struct {
+	db *sql.DB
+}
+
+// Listing is a rental with when it was first and last seen
+type Listing struct {
+	Rental
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// OnMarket is how long the listing has been seen
+func (l Listing) OnMarket() time.Duration {
+	return l.LastSeen.Sub(l.FirstSeen)
+}
+
+// PricePoint is a price of a rental since SeenAt
+type PricePoint struct {
+	Price      string
+	PriceValue int
+	SeenAt     time.Time
+}
+
+// OpenStore open or create a sqlite database at filename
+func OpenStore(filename string) (*Store, error) {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return nil, fmt.Errorf("open store %s error %v", filename, err)
+	}
+	// sqlite allow one writer, a single connection avoid SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(storeSchema)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create store schema error %v", err)
+	}
+
+	s := &Store{db: db}
+	err = s.migrate()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// migrate add the scope column to databases created before scopes, their rentals belong to the "" scope
+func (s *Store) migrate() error {
+	var n int
+	err := s.db.QueryRow(`SELECT count(*) FROM pragma_table_info('rentals') WHERE name = 'scope'`).Scan(&n)
+	if err != nil {
+		return fmt.Errorf("query store schema error %v", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	_, err = s.db.Exec(`ALTER TABLE rentals ADD COLUMN scope TEXT NOT NULL DEFAULT ''`)
+	if err != nil {
+		return fmt.Errorf("add scope column error %v", err)
+	}
+	_, err = s.db.Exec(`INSERT OR IGNORE INTO runs (scope, seen_at) SELECT DISTINCT '', last_seen FROM rentals`)
+	if err != nil {
+		return fmt.Errorf("add runs error %v", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save upsert rentals of scope seen at seenAt, a price is added to history when the rental is new or its price changed.
+// Every rental of one scrape run should be saved with the same scope and seenAt, Active depend on it.
+func (s *Store) Save(scope string, rentals Rentals, seenAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction error %v", err)
+	}
+	defer tx.Rollback()
+
+	seen := seenAt.Unix()
+	_, err = tx.Exec(`INSERT OR IGNORE INTO runs (scope, seen_at) VALUES (?, ?)`, scope, seen)
+	if err != nil {
+		return fmt.Errorf("save run error %v", err)
+	}
+
+	for _, r := range rentals {
+		if r.ID == "" {
+			continue
+		}
+
+		_, err := tx.Exec(`
+INSERT INTO rentals (`+rentalColumns+`, scope, price_value, ping_value)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	title = excluded.title, url = excluded.url, post_by = excluded.post_by, phone = excluded.phone,
+	price = excluded.price, section = excluded.section, address = excluded.address,
+	community = excluded.community, option_type = excluded.option_type, ping = excluded.ping,
+	floor = excluded.floor, layout = excluded.layout, price_value = excluded.price_value,
+	ping_value = excluded.ping_value,
+	scope = CASE WHEN excluded.last_seen >= last_seen THEN excluded.scope ELSE scope END,
+	first_seen = min(first_seen, excluded.first_seen), last_seen = max(last_seen, excluded.last_seen)`,
+			r.ID, r.Title, r.URL, r.PostBy, r.Phone, r.Price, r.Section, r.Address, r.Community,
+			r.OptionType, r.Ping, r.Floor, r.Layout, seen, seen, scope, r.PriceValue, r.PingValue)
+		if err != nil {
+			return fmt.Errorf("save rental %s error %v", r.ID, err)
+		}
+
+		var last string
+		err = tx.QueryRow(`SELECT price FROM prices WHERE id = ? AND seen_at <= ? ORDER BY seen_at DESC LIMIT 1`, r.ID, seen).Scan(&last)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("query price of %s error %v", r.ID, err)
+		}
+		if err == nil && last == r.Price {
+			continue
+		}
+
+		_, err = tx.Exec(`INSERT OR REPLACE INTO prices (id, price, price_value, seen_at) VALUES (?, ?, ?, ?)`, r.ID, r.Price, r.PriceValue, seen)
+		if err != nil {
+			return fmt.Errorf("save price of %s error %v", r.ID, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction error %v", err)
+	}
+
+	return nil
+}
+
+// latestRun select seen_at of the latest Save of a scope
+const latestRun = `(SELECT max(seen_at) FROM runs WHERE scope = ?)`
+
+// Active return listings of scope seen by the latest Save of scope
+func (s *Store) Active(scope string) ([]Listing, error) {
+	return s.listings(`WHERE scope = ? AND last_seen = `+latestRun+` ORDER BY first_seen, id`, scope, scope)
+}
+
+// GoneSince return listings of scope seen at or after since but not by the latest Save of scope, ordered by last seen
+func (s *Store) GoneSince(scope string, since time.Time) ([]Listing, error) {
+	return s.listings(`WHERE scope = ? AND last_seen >= ? AND last_seen < `+latestRun+` ORDER BY last_seen, id`, scope, since.Unix(), scope)
+}
+
+// Listing return a listing by ID, sql.ErrNoRows if it was never saved
+func (s *Store) Listing(id string) (Listing, error) {
+	listings, err := s.listings(`WHERE id = ?`, id)
+	if err != nil {
+		return Listing{}, err
+	}
+	if len(listings) == 0 {
+		return Listing{}, sql.ErrNoRows
+	}
+
+	return listings[0], nil
+}
+
+// PriceHistory return prices of a rental from the oldest
+func (s *Store) PriceHistory(id string) ([]PricePoint, error) {
+	rows, err := s.db.Query(`SELECT price, price_value, seen_at FROM prices WHERE id = ? ORDER BY seen_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query price history of %s error %v", id, err)
+	}
+	defer rows.Close()
+
+	history := []PricePoint{}
+	for rows.Next() {
+		var p PricePoint
+		var seenAt int64
+		err := rows.Scan(&p.Price, &p.PriceValue, &seenAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan price history error %v", err)
+		}
+		p.SeenAt = time.Unix(seenAt, 0)
+		history = append(history, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query price history of %s error %v", id, err)
+	}
+
+	return history, nil
+}
+
+func (s *Store) listings(where string, args ...interface{}) ([]Listing, error) {
+	rows, err := s.db.Query(`SELECT `+rentalColumns+` FROM rentals `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listings error %v", err)
+	}
+	defer rows.Close()
+
+	listings := []Listing{}
+	for rows.Next() {
+		var l Listing
+		var firstSeen, lastSeen int64
+		err := rows.Scan(&l.ID, &l.Title, &l.URL, &l.PostBy, &l.Phone, &l.Price, &l.Section, &l.Address,
+			&l.Community, &l.OptionType, &l.Ping, &l.Floor, &l.Layout, &firstSeen, &lastSeen)
+		if err != nil {
+			return nil, fmt.Errorf("scan listing error %v", err)
+		}
+		l.parseNumbers()
+		l.FirstSeen, l.LastSeen = time.Unix(firstSeen, 0), time.Unix(lastSeen, 0)
+		listings = append(listings, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query listings error %v", err)
+	}
+
+	return listings, nil
+}
@@ -0,0 +1,190 @@
+package scraper
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T) (*Store, func()) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := OpenStore(filepath.Join(dir, "rentals.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return store, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestStore(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	day1 := time.Date(2020, 7, 1, 9, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	rental := func(id, title, price string) Rental {
+		r := Rental{ID: id, Title: title, Price: price, Ping: "8坪", Floor: "樓層：3/5", Phone: "0912"}
+		r.parseNumbers()
+		return r
+	}
+
+	assert.Nil(t, store.Save("台北市", Rentals{rental("1", "套房", "12,000 元/月"), rental("2", "雅房", "8,000 元/月")}, day1))
+	assert.Nil(t, store.Save("台北市", Rentals{rental("1", "套房", "11,000 元/月"), rental("3", "整層", "30,000 元/月")}, day2))
+	assert.Nil(t, store.Save("台北市", Rentals{rental("1", "近捷運套房", "11,000 元/月"), {Title: "no id"}}, day3))
+
+	active, err := store.Active("台北市")
+	assert.Nil(t, err)
+	assert.Len(t, active, 1)
+	assert.Equal(t, "1", active[0].ID)
+	assert.Equal(t, "近捷運套房", active[0].Title)
+	assert.Equal(t, "0912", active[0].Phone)
+	assert.Equal(t, 11000, active[0].PriceValue)
+	assert.Equal(t, 3, active[0].FloorValue)
+	assert.True(t, day1.Equal(active[0].FirstSeen))
+	assert.True(t, day3.Equal(active[0].LastSeen))
+	assert.Equal(t, 48*time.Hour, active[0].OnMarket())
+
+	gone, err := store.GoneSince("台北市", day1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2", "3"}, []string{gone[0].ID, gone[1].ID})
+
+	gone, err = store.GoneSince("台北市", day2)
+	assert.Nil(t, err)
+	assert.Len(t, gone, 1)
+	assert.Equal(t, "3", gone[0].ID)
+
+	history, err := store.PriceHistory("1")
+	assert.Nil(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, PricePoint{Price: "12,000 元/月", PriceValue: 12000, SeenAt: day1}, history[0])
+	assert.Equal(t, 11000, history[1].PriceValue)
+	assert.True(t, day2.Equal(history[1].SeenAt))
+
+	history, err = store.PriceHistory("404")
+	assert.Nil(t, err)
+	assert.Len(t, history, 0)
+
+	listing, err := store.Listing("2")
+	assert.Nil(t, err)
+	assert.Equal(t, "雅房", listing.Title)
+	_, err = store.Listing("404")
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestOpenStore_Reopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.db")
+
+	store, err := OpenStore(filename)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save("台北市", Rentals{{ID: "1", Price: "1,000 元/月"}}, time.Now()))
+	assert.Nil(t, store.Close())
+
+	store, err = OpenStore(filename)
+	assert.Nil(t, err)
+	defer store.Close()
+	active, err := store.Active("台北市")
+	assert.Nil(t, err)
+	assert.Len(t, active, 1)
+}
+
+func TestStore_Scopes(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	day1 := time.Date(2020, 7, 1, 9, 0, 0, 0, time.Local)
+	taipei := day1.Add(time.Hour)
+	taichung := day1.Add(2 * time.Hour)
+	day2 := day1.AddDate(0, 0, 1)
+
+	assert.Nil(t, store.Save("台北市", Rentals{{ID: "1", Price: "20,000 元/月"}, {ID: "2", Price: "15,000 元/月"}}, taipei))
+	assert.Nil(t, store.Save("台中市", Rentals{{ID: "3", Price: "9,000 元/月"}}, taichung))
+
+	active, err := store.Active("台北市")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, listingIDs(active))
+	active, err = store.Active("台中市")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"3"}, listingIDs(active))
+	gone, err := store.GoneSince("台北市", day1)
+	assert.Nil(t, err)
+	assert.Len(t, gone, 0)
+
+	// 台北市 is saved again the next day without rental 2, 台中市 is untouched
+	assert.Nil(t, store.Save("台北市", Rentals{{ID: "1", Price: "20,000 元/月"}}, day2))
+
+	gone, err = store.GoneSince("台北市", day1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2"}, listingIDs(gone))
+	active, err = store.Active("台中市")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"3"}, listingIDs(active))
+	gone, err = store.GoneSince("台中市", day1)
+	assert.Nil(t, err)
+	assert.Len(t, gone, 0)
+
+	// a scope saving nothing delist all its rentals
+	assert.Nil(t, store.Save("台中市", nil, day2))
+	active, err = store.Active("台中市")
+	assert.Nil(t, err)
+	assert.Len(t, active, 0)
+}
+
+func TestOpenStore_Migrate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.db")
+
+	// a database saved before scopes
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldSchema := strings.Replace(storeSchema, "\tscope        TEXT NOT NULL DEFAULT '',\n", "", 1)
+	_, err = db.Exec(oldSchema)
+	assert.Nil(t, err)
+	_, err = db.Exec(`INSERT INTO rentals (` + rentalColumns + `, price_value, ping_value) VALUES ('1', '', '', '', '', '', '', '', '', '', '', '', '', 100, 100, 0, 0)`)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Close())
+
+	store, err := OpenStore(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	active, err := store.Active("")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1"}, listingIDs(active))
+}
+
+func listingIDs(listings []Listing) []string {
+	ids := []string{}
+	for _, l := range listings {
+		ids = append(ids, l.ID)
+	}
+
+	return ids
+}
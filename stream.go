@@ -0,0 +1,48 @@
+package scraper
+
+import (
+	"context"
+)
+
+// PageInfo tell where a rental is scraped from
+type PageInfo struct {
+	Section string // section code of the query
+	Page    int    // page number, starts from 1
+	Pages   int    // total pages of the section
+	Records int    // total records of the section
+}
+
+// RentalHandler is called by ScrapeRentalsFunc with each scraped rental
+type RentalHandler func(rental Rental, page PageInfo) error
+
+// ScrapedRental is a rental delivered by ScrapeRentalsChan
+type ScrapedRental struct {
+	Rental
+	Page PageInfo
+}
+
+// ScrapeRentalsChan is ScrapeRentalsFunc delivering rentals over a channel.
+// The rental channel is closed once scraping is done, then the error channel receive the result of ScrapeRentalsFunc.
+// Stop receiving rentals by canceling ctx.
+func (f *FiveN1) ScrapeRentalsChan(ctx context.Context, query *Query) (<-chan ScrapedRental, <-chan error) {
+	rentals := make(chan ScrapedRental)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+
+		err := f.ScrapeRentalsFunc(ctx, query, func(rental Rental, page PageInfo) error {
+			select {
+			case rentals <- ScrapedRental{Rental: rental, Page: page}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(rentals)
+
+		errc <- err
+	}()
+
+	return rentals, errc
+}
@@ -0,0 +1,96 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newItems2Server() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		html, _ := ioutil.ReadFile("test_fixture/591with2items.html")
+		_, _ = w.Write(html)
+	}))
+}
+
+func TestFiveN1_ScrapeRentalsFunc(t *testing.T) {
+	t.Run("call handler with section and page", func(t *testing.T) {
+		server := newItems2Server()
+		defer server.Close()
+		query := &Query{
+			RootURL: server.URL + "/?",
+			Section: "98,99",
+		}
+
+		var got []PageInfo
+		scraper := NewFiveN1()
+		err := scraper.ScrapeRentalsFunc(context.Background(), query, func(rental Rental, page PageInfo) error {
+			assert.Equal(t, page.Section, rental.Section)
+			got = append(got, page)
+			return nil
+		})
+
+		assert.Nil(t, err)
+		want := []PageInfo{
+			{Section: "98", Page: 1, Pages: 1, Records: 2},
+			{Section: "98", Page: 1, Pages: 1, Records: 2},
+			{Section: "99", Page: 1, Pages: 1, Records: 2},
+			{Section: "99", Page: 1, Pages: 1, Records: 2},
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("stop when handler return error", func(t *testing.T) {
+		requested := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			html, _ := ioutil.ReadFile("test_fixture/591with2items.html")
+			_, _ = w.Write(html)
+		}))
+		defer server.Close()
+		query := &Query{
+			RootURL: server.URL + "/?",
+			Section: "98,99,100",
+		}
+
+		errFull := errors.New("disk full")
+		called := 0
+		scraper := NewFiveN1()
+		err := scraper.ScrapeRentalsFunc(context.Background(), query, func(Rental, PageInfo) error {
+			called++
+			return errFull
+		})
+
+		assert.Equal(t, errFull, err)
+		assert.Equal(t, 1, called)
+		assert.Equal(t, 2, requested)
+	})
+}
+
+func TestFiveN1_ScrapeRentalsChan(t *testing.T) {
+	server := newItems2Server()
+	defer server.Close()
+	query := &Query{
+		RootURL: server.URL + "/?",
+		Section: "98,99,100",
+	}
+
+	scraper := NewFiveN1()
+	rentals, errc := scraper.ScrapeRentalsChan(context.Background(), query)
+
+	var got []ScrapedRental
+	for rental := range rentals {
+		got = append(got, rental)
+	}
+
+	assert.Nil(t, <-errc)
+	assert.Equal(t, 6, len(got))
+	assert.Equal(t, "R9538360", got[0].ID)
+	assert.Equal(t, "100", got[5].Section)
+	assert.Equal(t, "100", got[5].Page.Section)
+}
@@ -0,0 +1,163 @@
+package scraper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Summary is statistics of rentals in a section, prices failed to parse are not counted in prices
+type Summary struct {
+	Section            string
+	Count              int
+	MedianPrice        float64
+	MeanPrice          float64
+	MedianPricePerPing float64
+}
+
+// Summarize return statistics of rentals as section
+func (r Rentals) Summarize(section string) Summary {
+	s := Summary{Section: section, Count: len(r)}
+
+	var prices, perPings []float64
+	for _, rental := range r {
+		if _, failed := rental.ParseErrors["price"]; failed || rental.PriceValue == 0 {
+			continue
+		}
+		prices = append(prices, float64(rental.PriceValue))
+
+		if _, failed := rental.ParseErrors["ping"]; !failed && rental.PingValue > 0 {
+			perPings = append(perPings, float64(rental.PriceValue)/rental.PingValue)
+		}
+	}
+
+	s.MedianPrice = median(prices)
+	s.MeanPrice = mean(prices)
+	s.MedianPricePerPing = median(perPings)
+	return s
+}
+
+// GroupBySection group rentals by Section in the order sections first appear
+func (r Rentals) GroupBySection() (sections []string, groups map[string]Rentals) {
+	groups = map[string]Rentals{}
+	for _, rental := range r {
+		if _, ok := groups[rental.Section]; !ok {
+			sections = append(sections, rental.Section)
+		}
+		groups[rental.Section] = append(groups[rental.Section], rental)
+	}
+
+	return sections, groups
+}
+
+// SummarizeBySection return Summary of every section in the order sections first appear
+func (r Rentals) SummarizeBySection() []Summary {
+	sections, groups := r.GroupBySection()
+
+	summaries := []Summary{}
+	for _, section := range sections {
+		summaries = append(summaries, groups[section].Summarize(section))
+	}
+
+	return summaries
+}
+
+// SaveAsXLSXBySection save a 總覽 sheet summarizing every section and 全部,
+// followed by a sheet of rentals for each section like SaveAsXLSX.
+func (r Rentals) SaveAsXLSXBySection(filename string, columns ...Column) error {
+	columns = orDefault(columns)
+	sections, groups := r.GroupBySection()
+
+	x := newXlsx()
+	err := x.NewSheet("總覽")
+	if err != nil {
+		return err
+	}
+	err = x.WriteHeader("區", "數量", "租金中位數", "平均租金", "每坪租金中位數")
+	if err != nil {
+		return fmt.Errorf("xlsx.WriteHeader error %v", err)
+	}
+	for col := 3; col <= 5; col++ {
+		x.SetNumberFormat(col, "#,##0")
+	}
+
+	summaries := append(r.SummarizeBySection(), r.Summarize("全部"))
+	for _, s := range summaries {
+		err := x.WriteNextRow(sectionName(s.Section), s.Count, s.MedianPrice, s.MeanPrice, s.MedianPricePerPing)
+		if err != nil {
+			return fmt.Errorf("xlsx.WriteNextRow error %v", err)
+		}
+	}
+
+	used := map[string]bool{"總覽": true}
+	for _, section := range sections {
+		err := x.NewSheet(sheetName(sectionName(section), used))
+		if err != nil {
+			return err
+		}
+		err = groups[section].writeXLSX(x, columns)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = x.Save(filename)
+	if err != nil {
+		return fmt.Errorf("xlsx save file error %v", err)
+	}
+
+	return nil
+}
+
+// sectionName name rentals without section
+func sectionName(section string) string {
+	if section == "" {
+		return "未分區"
+	}
+
+	return section
+}
+
+// sheetName make name a valid and unused sheet name, which is at most 31 characters without []:*?/\
+func sheetName(name string, used map[string]bool) string {
+	name = strings.NewReplacer("[", "", "]", "", ":", "", "*", "", "?", "", "/", "", "\\", "").Replace(name)
+	if runes := []rune(name); len(runes) > 28 {
+		name = string(runes[:28])
+	}
+
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s(%d)", name, i)
+	}
+	used[unique] = true
+
+	return unique
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
@@ -0,0 +1,82 @@
+package scraper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSummaryRentals() Rentals {
+	return parsedRentals(Rentals{
+		{ID: "R1", Title: "a", Section: "中正區", Price: "10,000 元/月", Ping: "10坪"},
+		{ID: "R2", Title: "b", Section: "大安區", Price: "30,000 元/月", Ping: "20坪"},
+		{ID: "R3", Title: "c", Section: "中正區", Price: "20,000 元/月", Ping: "5坪"},
+		{ID: "R4", Title: "d", Section: "中正區", Price: "面議", Ping: "8坪"},
+		{ID: "R5", Title: "e", Section: "中正區", Price: "12,000 元/月", Ping: "坪數未知"},
+		{ID: "R6", Title: "f", Section: "", Price: "9,000 元/月", Ping: "3坪"},
+	})
+}
+
+func TestRentals_SummarizeBySection(t *testing.T) {
+	summaries := testSummaryRentals().SummarizeBySection()
+
+	assert.Equal(t, []Summary{
+		{Section: "中正區", Count: 4, MedianPrice: 12000, MeanPrice: 14000, MedianPricePerPing: 2500},
+		{Section: "大安區", Count: 1, MedianPrice: 30000, MeanPrice: 30000, MedianPricePerPing: 1500},
+		{Section: "", Count: 1, MedianPrice: 9000, MeanPrice: 9000, MedianPricePerPing: 3000},
+	}, summaries)
+
+	assert.Equal(t, Summary{Section: "全部"}, Rentals{}.Summarize("全部"))
+}
+
+func TestMedian(t *testing.T) {
+	assert.Equal(t, float64(0), median(nil))
+	assert.Equal(t, float64(2), median([]float64{3, 1, 2}))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+}
+
+func TestSheetName(t *testing.T) {
+	used := map[string]bool{"總覽": true}
+	assert.Equal(t, "中正區", sheetName("中正區", used))
+	assert.Equal(t, "中正區(2)", sheetName("中正區", used))
+	assert.Equal(t, "總覽(2)", sheetName("總覽", used))
+	assert.Equal(t, "ab", sheetName("a/b", used))
+	assert.Len(t, []rune(sheetName("一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十", used)), 28)
+}
+
+func TestRentals_SaveAsXLSXBySection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.xlsx")
+
+	rentals := testSummaryRentals()
+	assert.Nil(t, rentals.SaveAsXLSXBySection(filename))
+
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"總覽", "中正區", "大安區", "未分區"}, f.GetSheetList())
+
+	rows, err := f.GetRows("總覽")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"區", "數量", "租金中位數", "平均租金", "每坪租金中位數"}, rows[0])
+	assert.Equal(t, []string{"中正區", "4", "12000", "14000", "2500"}, rows[1])
+	assert.Equal(t, []string{"全部", "6"}, rows[4][:2])
+
+	rows, err = f.GetRows("中正區")
+	assert.Nil(t, err)
+	assert.Len(t, rows, 5)
+	assert.Equal(t, "區", rows[0][0])
+
+	loaded, err := LoadXLSX(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "c", "d", "e", "b", "f"}, []string{loaded[0].Title, loaded[1].Title, loaded[2].Title, loaded[3].Title, loaded[4].Title, loaded[5].Title}, "總覽 is skipped")
+}
@@ -6,10 +6,34 @@ import (
 	"github.com/360EntSecGroup-Skylar/excelize/v2"
 )
 
+const (
+	headerStyle = `{"font":{"bold":true}}`
+	linkStyle   = `{"font":{"color":"#1265BE","underline":"single"}}`
+
+	minColWidth = 8
+	maxColWidth = 60
+
+	// maxHyperLinks is how many hyperlinks excelize accept in a worksheet, links past it are written as plain text
+	maxHyperLinks = 65530
+)
+
+// xlsxLink is a cell value written as a clickable hyperlink
+type xlsxLink string
+
 type xlsx struct {
 	f            *excelize.File
 	currentRow   int
 	currentSheet string
+	sheets       int // sheets added by NewSheet
+
+	// of the current sheet
+	headerCols int            // columns of the header written by WriteHeader, 0 without header
+	links      int            // hyperlinks set
+	maxCols    int            // columns of the widest row
+	widths     map[int]int    // display width of the widest value by column
+	formats    map[int]string // number format by column
+
+	styles map[string]int // style id by style json
 }
 
 func newXlsx() *xlsx {
@@ -18,9 +42,32 @@ func newXlsx() *xlsx {
 		f:            f,
 		currentRow:   1,
 		currentSheet: f.GetSheetName(f.GetActiveSheetIndex()),
+		widths:       map[int]int{},
+		formats:      map[int]string{},
+		styles:       map[string]int{},
 	}
 }
 
+// WriteHeader write a bold header row, which is frozen and filtered when the sheet is finished
+func (x *xlsx) WriteHeader(values ...interface{}) error {
+	row := x.currentRow
+	err := x.WriteNextRow(values...)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	x.headerCols = len(values)
+	return x.setStyle(1, row, len(values), row, headerStyle)
+}
+
+// SetNumberFormat apply number format to numbers written into col later, ex: "#,##0"
+func (x *xlsx) SetNumberFormat(col int, format string) {
+	x.formats[col] = format
+}
+
 func (x *xlsx) WriteNextRow(values ...interface{}) error {
 	for i, value := range values {
 		col := i + 1
@@ -29,21 +76,197 @@ func (x *xlsx) WriteNextRow(values ...interface{}) error {
 			return fmt.Errorf("covert cell name error %v", err)
 		}
 
-		err = x.f.SetCellValue(x.currentSheet, axis, value)
+		err = x.setCell(axis, col, value)
 		if err != nil {
-			return fmt.Errorf("set cell value error %v", err)
+			return err
 		}
+		x.fitWidth(col, value)
 	}
 
+	if len(values) > x.maxCols {
+		x.maxCols = len(values)
+	}
 	x.currentRow++
 	return nil
 }
 
+func (x *xlsx) setCell(axis string, col int, value interface{}) error {
+	if link, ok := value.(xlsxLink); ok {
+		err := x.f.SetCellValue(x.currentSheet, axis, string(link))
+		if err != nil {
+			return fmt.Errorf("set cell value error %v", err)
+		}
+		if link == "" || x.links >= maxHyperLinks {
+			return nil
+		}
+
+		x.links++
+		err = x.f.SetCellHyperLink(x.currentSheet, axis, string(link), "External")
+		if err != nil {
+			return fmt.Errorf("set cell hyperlink error %v", err)
+		}
+		return x.setStyle(col, x.currentRow, col, x.currentRow, linkStyle)
+	}
+
+	err := x.f.SetCellValue(x.currentSheet, axis, value)
+	if err != nil {
+		return fmt.Errorf("set cell value error %v", err)
+	}
+
+	if format, ok := x.formats[col]; ok && isNumber(value) {
+		return x.setStyle(col, x.currentRow, col, x.currentRow, fmt.Sprintf(`{"custom_number_format":%q}`, format))
+	}
+
+	return nil
+}
+
+// setStyle apply style json to cells from col1,row1 to col2,row2
+func (x *xlsx) setStyle(col1, row1, col2, row2 int, style string) error {
+	id, ok := x.styles[style]
+	if !ok {
+		var err error
+		id, err = x.f.NewStyle(style)
+		if err != nil {
+			return fmt.Errorf("new style error %v", err)
+		}
+		x.styles[style] = id
+	}
+
+	from, err := excelize.CoordinatesToCellName(col1, row1)
+	if err != nil {
+		return fmt.Errorf("covert cell name error %v", err)
+	}
+	to, err := excelize.CoordinatesToCellName(col2, row2)
+	if err != nil {
+		return fmt.Errorf("covert cell name error %v", err)
+	}
+
+	err = x.f.SetCellStyle(x.currentSheet, from, to, id)
+	if err != nil {
+		return fmt.Errorf("set cell style error %v", err)
+	}
+
+	return nil
+}
+
+func (x *xlsx) fitWidth(col int, value interface{}) {
+	if width := cellWidth(value); width > x.widths[col] {
+		x.widths[col] = width
+	}
+}
+
+// cellWidth is display width of a cell value
+func cellWidth(value interface{}) int {
+	if value == nil {
+		return 0
+	}
+
+	return displayWidth(fmt.Sprint(value))
+}
+
+// finishSheet freeze and filter the header and size columns of the current sheet
+func (x *xlsx) finishSheet() error {
+	if x.headerCols > 0 {
+		err := x.f.SetPanes(x.currentSheet, `{"freeze":true,"split":false,"x_split":0,"y_split":1,"top_left_cell":"A2","active_pane":"bottomLeft","panes":[{"sqref":"A2","active_cell":"A2","pane":"bottomLeft"}]}`)
+		if err != nil {
+			return fmt.Errorf("freeze header error %v", err)
+		}
+
+		last, err := excelize.CoordinatesToCellName(x.headerCols, x.currentRow-1)
+		if err != nil {
+			return fmt.Errorf("covert cell name error %v", err)
+		}
+		err = x.f.AutoFilter(x.currentSheet, "A1", last, "")
+		if err != nil {
+			return fmt.Errorf("auto filter error %v", err)
+		}
+	}
+
+	for col := 1; col <= x.maxCols; col++ {
+		name, err := excelize.ColumnNumberToName(col)
+		if err != nil {
+			return fmt.Errorf("covert column name error %v", err)
+		}
+
+		err = x.f.SetColWidth(x.currentSheet, name, name, colWidth(x.widths[col]))
+		if err != nil {
+			return fmt.Errorf("set column width error %v", err)
+		}
+	}
+
+	return nil
+}
+
+// NewSheet add a sheet and write next rows into it, the first one rename the default sheet
+func (x *xlsx) NewSheet(name string) error {
+	if x.sheets == 0 {
+		x.f.SetSheetName(x.currentSheet, name)
+	} else {
+		err := x.finishSheet()
+		if err != nil {
+			return err
+		}
+		x.f.NewSheet(name)
+	}
+
+	x.sheets++
+	x.currentSheet = name
+	x.currentRow = 1
+	x.headerCols = 0
+	x.links = 0
+	x.maxCols = 0
+	x.widths = map[int]int{}
+	x.formats = map[int]string{}
+	return nil
+}
+
 func (x *xlsx) Save(filename string) error {
-	err := x.f.SaveAs(filename)
+	err := x.finishSheet()
+	if err != nil {
+		return err
+	}
+
+	err = x.f.SaveAs(filename)
 	if err != nil {
 		return fmt.Errorf("save as error %v", err)
 	}
 
 	return nil
 }
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int64, float64:
+		return true
+	}
+
+	return false
+}
+
+// displayWidth count CJK and full-width characters as 2 like they are shown
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if r >= 0x1100 && (r <= 0x115F || r >= 0x2E80 && r <= 0xA4CF || r >= 0xAC00 && r <= 0xD7A3 ||
+			r >= 0xF900 && r <= 0xFAFF || r >= 0xFE30 && r <= 0xFE4F || r >= 0xFF00 && r <= 0xFF60 || r >= 0xFFE0 && r <= 0xFFE6) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+
+	return width
+}
+
+// colWidth is display width with padding, limited so long titles and urls don't take the whole screen
+func colWidth(width int) float64 {
+	width += 2
+	if width < minColWidth {
+		width = minColWidth
+	}
+	if width > maxColWidth {
+		width = maxColWidth
+	}
+
+	return float64(width)
+}
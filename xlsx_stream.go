@@ -0,0 +1,202 @@
+package scraper
+
+import (
+	"fmt"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+const (
+	defaultStreamColWidth = 14
+	linkColWidth          = 50
+)
+
+// XLSXWriter append rentals to an xlsx file row by row through excelize's StreamWriter.
+// Only hyperlinks are kept in memory, and links past the excelize limit are written as plain text,
+// so memory stays bounded however many rentals are written. The file is only saved by Close.
+// It is not safe for concurrent use, use Handler to write rentals as they are scraped.
+type XLSXWriter struct {
+	f        *excelize.File
+	sw       *excelize.StreamWriter
+	sheet    string
+	filename string
+	columns  []Column
+	row      int   // next row
+	links    int   // hyperlinks set, they are kept in memory until Close
+	maxLinks int   // links past it are written as plain text, default maxHyperLinks
+	styles   []int // style id by column, 0 for no style
+}
+
+// NewXLSXWriter create an XLSXWriter writing columns like SaveAsXLSX,
+// columns are sized by their header as rentals are unknown yet.
+func NewXLSXWriter(filename string, columns ...Column) (*XLSXWriter, error) {
+	columns = orDefault(columns)
+
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = defaultStreamColWidth
+		if c.Link {
+			widths[i] = linkColWidth
+		}
+		if w := displayWidth(c.Header); w > widths[i] {
+			widths[i] = w
+		}
+	}
+
+	return newXLSXWriter(filename, columns, widths)
+}
+
+// newXLSXWriter create an XLSXWriter with display widths of columns
+func newXLSXWriter(filename string, columns []Column, widths []int) (*XLSXWriter, error) {
+	f := excelize.NewFile()
+	w := &XLSXWriter{
+		f:        f,
+		sheet:    f.GetSheetName(f.GetActiveSheetIndex()),
+		filename: filename,
+		columns:  columns,
+		row:      1,
+		maxLinks: maxHyperLinks,
+		styles:   make([]int, len(columns)),
+	}
+
+	// panes and widths must be set before the stream writer, which write them first
+	err := f.SetPanes(w.sheet, `{"freeze":true,"split":false,"x_split":0,"y_split":1,"top_left_cell":"A2","active_pane":"bottomLeft","panes":[{"sqref":"A2","active_cell":"A2","pane":"bottomLeft"}]}`)
+	if err != nil {
+		return nil, fmt.Errorf("freeze header error %v", err)
+	}
+	for i, width := range widths {
+		name, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return nil, fmt.Errorf("covert column name error %v", err)
+		}
+		err = f.SetColWidth(w.sheet, name, name, colWidth(width))
+		if err != nil {
+			return nil, fmt.Errorf("set column width error %v", err)
+		}
+	}
+
+	for i, c := range columns {
+		style := ""
+		switch {
+		case c.Link:
+			style = linkStyle
+		case c.Format != "":
+			style = fmt.Sprintf(`{"custom_number_format":%q}`, c.Format)
+		default:
+			continue
+		}
+
+		w.styles[i], err = f.NewStyle(style)
+		if err != nil {
+			return nil, fmt.Errorf("new style error %v", err)
+		}
+	}
+
+	w.sw, err = f.NewStreamWriter(w.sheet)
+	if err != nil {
+		return nil, fmt.Errorf("new stream writer error %v", err)
+	}
+
+	header, err := f.NewStyle(headerStyle)
+	if err != nil {
+		return nil, fmt.Errorf("new style error %v", err)
+	}
+	headers := make([]interface{}, len(columns))
+	for i, c := range columns {
+		headers[i] = excelize.Cell{StyleID: header, Value: c.Header}
+	}
+	err = w.writeRow(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write append a rental
+func (w *XLSXWriter) Write(r Rental) error {
+	values := xlsxValues(w.columns, r)
+	for i, value := range values {
+		if link, ok := value.(xlsxLink); ok {
+			values[i] = string(link)
+			if link == "" || w.links >= w.maxLinks {
+				continue
+			}
+
+			err := w.setHyperLink(i+1, string(link))
+			if err != nil {
+				return err
+			}
+			values[i] = excelize.Cell{StyleID: w.styles[i], Value: string(link)}
+			continue
+		}
+
+		if w.styles[i] != 0 && isNumber(value) {
+			values[i] = excelize.Cell{StyleID: w.styles[i], Value: value}
+		}
+	}
+
+	return w.writeRow(values)
+}
+
+// Handler return a RentalHandler writing every rental, ex: f.ScrapeRentalsFunc(ctx, q, w.Handler())
+func (w *XLSXWriter) Handler() RentalHandler {
+	return func(r Rental, _ PageInfo) error {
+		return w.Write(r)
+	}
+}
+
+// Close filter the header and save the file
+func (w *XLSXWriter) Close() error {
+	last, err := excelize.CoordinatesToCellName(len(w.columns), w.row-1)
+	if err != nil {
+		return fmt.Errorf("covert cell name error %v", err)
+	}
+	err = w.f.AutoFilter(w.sheet, "A1", last, "")
+	if err != nil {
+		return fmt.Errorf("auto filter error %v", err)
+	}
+
+	err = w.sw.Flush()
+	if err != nil {
+		return fmt.Errorf("flush stream writer error %v", err)
+	}
+
+	err = w.f.SaveAs(w.filename)
+	if err != nil {
+		return fmt.Errorf("save as error %v", err)
+	}
+
+	return nil
+}
+
+func (w *XLSXWriter) writeRow(values []interface{}) error {
+	axis, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return fmt.Errorf("covert cell name error %v", err)
+	}
+
+	err = w.sw.SetRow(axis, values)
+	if err != nil {
+		return fmt.Errorf("set row error %v", err)
+	}
+
+	w.row++
+	return nil
+}
+
+// setHyperLink add the link of a cell in the current row, links are kept in memory until Close
+func (w *XLSXWriter) setHyperLink(col int, link string) error {
+	axis, err := excelize.CoordinatesToCellName(col, w.row)
+	if err != nil {
+		return fmt.Errorf("covert cell name error %v", err)
+	}
+
+	err = w.f.SetCellHyperLink(w.sheet, axis, link, "External")
+	if err != nil {
+		return fmt.Errorf("set cell hyperlink error %v", err)
+	}
+	w.links++
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package scraper
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXLSXWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.xlsx")
+
+	columns, err := SelectColumns("id", "price", "url")
+	assert.Nil(t, err)
+	w, err := NewXLSXWriter(filename, columns...)
+	assert.Nil(t, err)
+
+	const n = 5000
+	for i := 0; i < n; i++ {
+		id := strconv.Itoa(i)
+		rental := Rental{ID: "R" + id, Price: id + " 元/月", URL: "https://rent.591.com.tw/rent-detail-" + id + ".html"}
+		rental.parseNumbers()
+		assert.Nil(t, w.Write(rental))
+	}
+	assert.Nil(t, w.Close())
+
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := f.GetRows("Sheet1")
+	assert.Nil(t, err)
+	assert.Len(t, rows, n+1)
+	assert.Equal(t, []string{"編號", "租金", "連結"}, rows[0])
+	assert.Equal(t, []string{"R4999", "4999", "https://rent.591.com.tw/rent-detail-4999.html"}, rows[n])
+
+	link, target, err := f.GetCellHyperLink("Sheet1", "C5001")
+	assert.Nil(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://rent.591.com.tw/rent-detail-4999.html", target)
+
+	sheet := xlsxPart(t, filename, "xl/worksheets/sheet1.xml")
+	assert.Regexp(t, `<pane [^>]*state="frozen"`, sheet)
+	assert.Regexp(t, `<autoFilter ref="A1:C5001"`, sheet)
+	assert.Regexp(t, `<col [^>]*max="3" min="3" width="52"`, sheet, "link column is wide")
+
+	loaded, err := LoadXLSX(filename)
+	assert.Nil(t, err)
+	assert.Len(t, loaded, n)
+	assert.Equal(t, 4999, loaded[n-1].PriceValue)
+}
+
+func TestXLSXWriter_ManyLinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	columns, err := SelectColumns("id", "url")
+	assert.Nil(t, err)
+	newRental := func(i int) Rental {
+		return Rental{ID: "R" + strconv.Itoa(i), URL: "https://rent.591.com.tw/rent-detail-" + strconv.Itoa(i) + ".html"}
+	}
+
+	t.Run("write plain urls when rentals are more than a sheet could link", func(t *testing.T) {
+		filename := filepath.Join(dir, "rentals.xlsx")
+		const n = maxHyperLinks + 70
+		rentals := make(Rentals, n)
+		for i := range rentals {
+			rentals[i] = newRental(i)
+		}
+		assert.Nil(t, rentals.SaveAsXLSX(filename, columns...))
+
+		f, err := excelize.OpenFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range []int{2, n + 1} {
+			axis := "B" + strconv.Itoa(row)
+			link, _, err := f.GetCellHyperLink("Sheet1", axis)
+			assert.Nil(t, err)
+			assert.False(t, link, axis)
+			value, err := f.GetCellValue("Sheet1", axis)
+			assert.Nil(t, err)
+			assert.Equal(t, rentals[row-2].URL, value)
+		}
+	})
+
+	t.Run("stop linking at the limit", func(t *testing.T) {
+		filename := filepath.Join(dir, "stream.xlsx")
+		w, err := NewXLSXWriter(filename, columns...)
+		assert.Nil(t, err)
+		w.maxLinks = 2
+		for i := 0; i < 3; i++ {
+			assert.Nil(t, w.Write(newRental(i)))
+		}
+		assert.Nil(t, w.Close())
+
+		f, err := excelize.OpenFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		link, _, _ := f.GetCellHyperLink("Sheet1", "B3")
+		assert.True(t, link)
+		link, _, _ = f.GetCellHyperLink("Sheet1", "B4")
+		assert.False(t, link)
+		value, _ := f.GetCellValue("Sheet1", "B4")
+		assert.Equal(t, newRental(2).URL, value)
+	})
+}
+
+func TestXLSXWriter_Handler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rentals.xlsx")
+
+	server := newItems2Server()
+	defer server.Close()
+	query := &Query{
+		RootURL: server.URL + "/?",
+		Section: "98,99",
+	}
+
+	w, err := NewXLSXWriter(filename)
+	assert.Nil(t, err)
+	err = NewFiveN1().ScrapeRentalsFunc(context.Background(), query, w.Handler())
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	loaded, err := LoadXLSX(filename)
+	assert.Nil(t, err)
+	assert.Len(t, loaded, 4)
+	assert.NotEqual(t, "", loaded[0].Title)
+}
@@ -0,0 +1,27 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := map[string]int{
+		"":            0,
+		"abc":         3,
+		"近捷運套房":       10,
+		"12,000 元/月":  12,
+		"ＡＢＣ":         6,
+		"https://591": 11,
+	}
+	for s, want := range tests {
+		assert.Equal(t, want, displayWidth(s), s)
+	}
+}
+
+func TestColWidth(t *testing.T) {
+	assert.Equal(t, float64(minColWidth), colWidth(0))
+	assert.Equal(t, float64(12), colWidth(10))
+	assert.Equal(t, float64(maxColWidth), colWidth(200))
+}